@@ -0,0 +1,173 @@
+package zerocfg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// CacheOpt configures Cached.
+type CacheOpt func(*cached)
+
+// CacheMaxStale returns a CacheOpt that refuses a cache saved more than d ago. Zero means no limit.
+func CacheMaxStale(d time.Duration) CacheOpt {
+	return func(c *cached) {
+		c.maxStale = d
+	}
+}
+
+// CacheLogf returns a CacheOpt that sets the function reporting the fallback to the cache
+// and failures to save it. The default is log.Printf.
+func CacheLogf(logf func(format string, args ...any)) CacheOpt {
+	return func(c *cached) {
+		c.logf = logf
+	}
+}
+
+// snapshot is the content of a cache file.
+type snapshot struct {
+	Saved time.Time         `json:"saved"`
+	Type  string            `json:"type"`
+	Found map[string]string `json:"found"`
+}
+
+type cached struct {
+	wrapper
+	path     string
+	maxStale time.Duration
+	logf     func(format string, args ...any)
+
+	options map[string]OptionInfo
+	// fallback reports that the last Provide used the cache.
+	fallback bool
+}
+
+// Cached returns a provider keeping the last known good result of p in the file at path.
+// Every successful Provide saves the found values; when p fails, the saved values are used
+// instead and a warning is logged. Only options known to be non-secret are saved, so secret
+// options are missing from the fallback. Values of options which are no longer registered are ignored.
+//
+// Usage:
+//
+//	zfg.Parse(zfg.Cached(http.New(url), "/var/cache/app/config.json", zfg.CacheMaxStale(24*time.Hour)))
+func Cached(p Provider, path string, opts ...CacheOpt) Provider {
+	c := &cached{wrapper: wrapper{p}, path: path, logf: log.Printf}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+func (c *cached) Inspect(options map[string]OptionInfo) {
+	c.options = options
+	c.wrapper.Inspect(options)
+}
+
+func (c *cached) Provide(awaited map[string]bool, conv func(any) string) (found, unknown map[string]string, err error) {
+	return c.ProvideContext(context.Background(), awaited, conv)
+}
+
+func (c *cached) ProvideContext(ctx context.Context, awaited map[string]bool, conv func(any) string) (found, unknown map[string]string, err error) {
+	c.fallback = false
+
+	found, unknown, err = provide(ctx, c.p, awaited, conv)
+	if err == nil {
+		if saveErr := c.save(found); saveErr != nil {
+			c.logf("zerocfg: %s: save cache: %v", c.Type(), saveErr)
+		}
+
+		return found, unknown, nil
+	}
+
+	s, loadErr := c.load()
+	if errors.Is(loadErr, os.ErrNotExist) {
+		return nil, nil, err
+	}
+	if loadErr != nil {
+		return nil, nil, errors.Join(err, loadErr)
+	}
+
+	found = make(map[string]string, len(s.Found))
+	for k, v := range s.Found {
+		if _, ok := awaited[k]; ok {
+			found[k] = v
+		}
+	}
+
+	c.fallback = true
+	c.logf("zerocfg: %s: using cache %s saved at %s: %v", c.Type(), c.path, s.Saved.Format(time.RFC3339), err)
+
+	return found, map[string]string{}, nil
+}
+
+func (c *cached) save(found map[string]string) error {
+	s := snapshot{Saved: time.Now().UTC(), Type: c.Type(), Found: make(map[string]string, len(found))}
+	for k, v := range found {
+		if info, ok := c.options[k]; ok && !info.Secret {
+			s.Found[k] = v
+		}
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// write a temporary file first, so a crash never leaves a truncated cache
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), c.path)
+}
+
+func (c *cached) load() (snapshot, error) {
+	var s snapshot
+
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		return s, fmt.Errorf("read cache: %w", err)
+	}
+
+	if err = json.Unmarshal(data, &s); err != nil {
+		return s, fmt.Errorf("read cache %s: %w", c.path, err)
+	}
+
+	if age := time.Since(s.Saved); c.maxStale > 0 && age > c.maxStale {
+		return s, fmt.Errorf("cache %s saved %s ago: %w", c.path, age.Round(time.Second), ErrStaleCache)
+	}
+
+	return s, nil
+}
+
+func (c *cached) Source(key string) string {
+	if c.fallback {
+		return fmt.Sprintf("cache[%s]", c.path)
+	}
+
+	return c.wrapper.Source(key)
+}
+
+func (c *cached) Locate(key string) string {
+	if c.fallback {
+		return ""
+	}
+
+	return c.wrapper.Locate(key)
+}
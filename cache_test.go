@@ -0,0 +1,93 @@
+package zerocfg
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Cached(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.json")
+	unavailable := errors.New("service unavailable")
+
+	var logs []string
+	logf := func(format string, args ...any) {
+		logs = append(logs, fmt.Sprintf(format, args...))
+	}
+
+	c = testConfig()
+	Int("port", 0, "")
+	Str("password", "", "", Secret())
+
+	p := Cached(newMock(map[string]any{"port": 8080, "password": "qwerty", "removed": 1}), path, CacheLogf(logf))
+	err := Parse(p)
+	require.Equal(t, UnknownFieldError{mockType: {"removed"}}, err)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"port": "8080"`)
+	require.NotContains(t, string(data), "qwerty")
+	require.NotContains(t, string(data), "removed")
+	require.Empty(t, logs)
+
+	c = testConfig()
+	port := Int("port", 0, "")
+	password := Str("password", "default", "", Secret())
+
+	require.NoError(t, Parse(Cached(errProvider{typ: "http", err: unavailable}, path, CacheLogf(logf))))
+	require.Equal(t, 8080, *port)
+	require.Equal(t, "default", *password)
+	require.Equal(t, "cache["+path+"]", c.vs["port"].setSource)
+	require.Len(t, logs, 1)
+	require.Contains(t, logs[0], "zerocfg: http: using cache "+path)
+	require.Contains(t, logs[0], "service unavailable")
+}
+
+func Test_CachedError(t *testing.T) {
+	dir := t.TempDir()
+	unavailable := errors.New("service unavailable")
+
+	stale := filepath.Join(dir, "stale.json")
+	saved := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	require.NoError(t, os.WriteFile(stale, []byte(`{"saved": "`+saved+`", "found": {"port": "1"}}`), 0o600))
+
+	broken := filepath.Join(dir, "broken.json")
+	require.NoError(t, os.WriteFile(broken, []byte(`{`), 0o600))
+
+	tests := []struct {
+		name  string
+		path  string
+		opts  []CacheOpt
+		err   error
+		cause string
+	}{
+		{name: "no cache", path: filepath.Join(dir, "missing.json")},
+		{name: "stale", path: stale, opts: []CacheOpt{CacheMaxStale(time.Minute)}, err: ErrStaleCache},
+		{name: "broken", path: broken, cause: "read cache " + broken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c = testConfig()
+			Int("port", 0, "")
+
+			opts := append([]CacheOpt{CacheLogf(func(string, ...any) { t.Error("unexpected fallback") })}, tt.opts...)
+			err := Parse(Cached(errProvider{typ: "http", err: unavailable}, tt.path, opts...))
+			require.ErrorIs(t, err, unavailable)
+			if tt.err != nil {
+				require.ErrorIs(t, err, tt.err)
+			}
+			require.ErrorContains(t, err, tt.cause)
+		})
+	}
+
+	c = testConfig()
+	port := Int("port", 0, "")
+	require.NoError(t, Parse(Cached(errProvider{typ: "http", err: unavailable}, stale, CacheLogf(func(string, ...any) {}))))
+	require.Equal(t, 1, *port)
+}
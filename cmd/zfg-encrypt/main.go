@@ -0,0 +1,77 @@
+// Command zfg-encrypt encrypts a value for pasting into a config file read with zerocfg.Decrypt.
+//
+// Usage:
+//
+//	zfg-encrypt -generate > config.key
+//	zfg-encrypt -key-file config.key 'p@ssw0rd'
+//	echo -n 'p@ssw0rd' | ZEROCFG_KEY=... zfg-encrypt
+//
+// The value is read from stdin when it is not passed as an argument, which keeps it out of shell history.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/chaindead/zerocfg/enc"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "zfg-encrypt:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := flag.NewFlagSet("zfg-encrypt", flag.ContinueOnError)
+	keyFile := fs.String("key-file", "", "path to the base64 encoded key")
+	keyEnv := fs.String("key-env", "ZEROCFG_KEY", "variable holding the base64 encoded key, used without -key-file")
+	generate := fs.Bool("generate", false, "print a new random key")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *generate {
+		key, err := enc.GenerateKey()
+		if err != nil {
+			return err
+		}
+
+		_, err = fmt.Fprintln(stdout, key)
+
+		return err
+	}
+
+	key := enc.FromEnv(*keyEnv)
+	if *keyFile != "" {
+		key = enc.FromFile(keyFile)
+	}
+
+	var value string
+	switch fs.NArg() {
+	case 0:
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return fmt.Errorf("read value: %w", err)
+		}
+
+		value = strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r")
+	case 1:
+		value = fs.Arg(0)
+	default:
+		return fmt.Errorf("expected one value, got %d", fs.NArg())
+	}
+
+	encrypted, err := key.Encrypt(value)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(stdout, encrypted)
+
+	return err
+}
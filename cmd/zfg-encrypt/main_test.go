@@ -0,0 +1,39 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/chaindead/zerocfg/enc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	var key bytes.Buffer
+	require.NoError(t, run([]string{"-generate"}, nil, &key))
+	t.Setenv("ZEROCFG_KEY", strings.TrimSpace(key.String()))
+
+	tests := []struct {
+		name  string
+		args  []string
+		stdin string
+	}{
+		{name: "argument", args: []string{"p@ssw0rd"}},
+		{name: "stdin", stdin: "p@ssw0rd\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			require.NoError(t, run(tt.args, strings.NewReader(tt.stdin), &out))
+
+			plain, ok, err := enc.FromEnv("ZEROCFG_KEY").Decrypt(strings.TrimSpace(out.String()))
+			require.NoError(t, err)
+			require.True(t, ok)
+			require.Equal(t, "p@ssw0rd", plain)
+		})
+	}
+
+	require.ErrorContains(t, run([]string{"a", "b"}, nil, &bytes.Buffer{}), "expected one value, got 2")
+}
@@ -25,7 +25,7 @@ func defaultConfig() *config {
 
 var c = defaultConfig()
 
-func (c *config) add(key string, v Value, usage string, opts ...OptNode) {
+func (c *config) add(key string, v Value, usage string, opts ...OptNode) *node {
 	n := &node{
 		Name:        key,
 		Description: usage,
@@ -51,6 +51,8 @@ func (c *config) add(key string, v Value, usage string, opts ...OptNode) {
 
 		c.aliases[alias] = n.Name
 	}
+
+	return n
 }
 
 func errorKeyConflict(new *node, existing *node, err error) error {
@@ -89,3 +91,17 @@ func (c *config) awaited() map[string]bool {
 
 	return a
 }
+
+func (c *config) options() map[string]OptionInfo {
+	o := make(map[string]OptionInfo, len(c.vs)+len(c.aliases))
+
+	for k, n := range c.vs {
+		o[k] = OptionInfo{Type: n.Value.Type(), Secret: n.secret()}
+	}
+
+	for alias, k := range c.aliases {
+		o[alias] = o[k]
+	}
+
+	return o
+}
@@ -1,11 +1,17 @@
 package zerocfg
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/chaindead/zerocfg/flag"
 	"github.com/stretchr/testify/require"
 )
 
@@ -412,6 +418,378 @@ func Test_Priority(t *testing.T) {
 	require.Equal(t, expected, vs)
 }
 
+func Test_ParseWith(t *testing.T) {
+	flags := flag.New(flag.WithArgs([]string{"--port", "1", "--name", "flag"}))
+	first := newMock(map[string]any{"port": 2})
+	second := newMock(map[string]any{"port": 3, "name": "second"})
+
+	tests := []struct {
+		name string
+		opts []ParseOption
+		port int
+		src  string
+	}{
+		{name: "default", opts: []ParseOption{Providers(first, second)}, port: 1, src: "flag"},
+		{name: "flags at 1", opts: []ParseOption{Providers(first, second), FlagsAt(1)}, port: 2, src: "flag"},
+		{name: "flags last", opts: []ParseOption{Providers(first), Providers(second), FlagsAt(10)}, port: 2, src: mockType},
+		{name: "no flags", opts: []ParseOption{Providers(second, first), NoFlags()}, port: 3, src: mockType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c = testConfig()
+			c.parsers = []Provider{flags}
+
+			port := Int("port", 0, "")
+			Str("name", "", "")
+
+			require.NoError(t, ParseWith(tt.opts...))
+			require.Equal(t, tt.port, *port)
+			require.Equal(t, tt.src, c.vs["name"].setSource)
+		})
+	}
+}
+
+// slowMock blocks in Provide until release is closed.
+type slowMock struct {
+	*mockParser
+	release chan struct{}
+}
+
+func (m slowMock) Type() string {
+	return "slow"
+}
+
+func (m slowMock) Provide(awaited map[string]bool, conv func(any) string) (f, u map[string]string, err error) {
+	<-m.release
+
+	return m.mockParser.Provide(awaited, conv)
+}
+
+// ctxMock blocks in ProvideContext until ctx is done, unless it has no deadline.
+type ctxMock struct {
+	*mockParser
+}
+
+func (m ctxMock) ProvideContext(ctx context.Context, awaited map[string]bool, conv func(any) string) (f, u map[string]string, err error) {
+	if _, ok := ctx.Deadline(); !ok {
+		return m.Provide(awaited, conv)
+	}
+
+	<-ctx.Done()
+
+	return nil, nil, fmt.Errorf("request: %w", ctx.Err())
+}
+
+// panicMock panics in Provide.
+type panicMock struct {
+	*mockParser
+}
+
+func (m panicMock) Provide(map[string]bool, func(any) string) (f, u map[string]string, err error) {
+	panic("provider failed")
+}
+
+func Test_ParseContextPanic(t *testing.T) {
+	c = testConfig()
+	Int("port", 0, "")
+
+	// without a timeout or a cancelable context the provider runs in the caller goroutine
+	require.PanicsWithValue(t, "provider failed", func() {
+		_ = ParseContext(context.Background(), Providers(panicMock{newMock(nil)}))
+	})
+}
+
+func Test_ParseContext(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	values := map[string]any{"port": 1}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		opts []ParseOption
+		err  error
+		msg  string
+	}{
+		{
+			name: "plain provider timeout",
+			ctx:  context.Background(),
+			opts: []ParseOption{Providers(slowMock{newMock(values), release}), ProviderTimeout(10 * time.Millisecond)},
+			err:  context.DeadlineExceeded,
+			msg:  `parse "slow": timed out after 10ms`,
+		},
+		{
+			name: "context provider timeout",
+			ctx:  context.Background(),
+			opts: []ParseOption{Providers(newMock(values), Optional(ctxMock{newMock(values)})), ProviderTimeout(10 * time.Millisecond)},
+			err:  context.DeadlineExceeded,
+			msg:  `parse "mock": timed out after 10ms: request`,
+		},
+		{
+			name: "canceled",
+			ctx:  canceled,
+			opts: []ParseOption{Providers(slowMock{newMock(values), release})},
+			err:  context.Canceled,
+			msg:  `parse "slow": context canceled`,
+		},
+		{
+			name: "no deadline",
+			ctx:  context.Background(),
+			opts: []ParseOption{Providers(ctxMock{newMock(values)})},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c = testConfig()
+			port := Int("port", 0, "")
+
+			err := ParseContext(tt.ctx, tt.opts...)
+			if tt.err == nil {
+				require.NoError(t, err)
+				require.Equal(t, 1, *port)
+
+				return
+			}
+
+			require.ErrorIs(t, err, tt.err)
+			require.ErrorContains(t, err, tt.msg)
+		})
+	}
+}
+
+// delayMock returns its values or err after delay, tracking the number of running providers.
+// With a barrier it waits for the other providers of the barrier instead, giving up after a second.
+type delayMock struct {
+	*mockParser
+	name    string
+	delay   time.Duration
+	err     error
+	running *atomic.Int32
+	peak    *atomic.Int32
+	barrier *sync.WaitGroup
+}
+
+func (m delayMock) Type() string {
+	return m.name
+}
+
+func (m delayMock) Provide(awaited map[string]bool, conv func(any) string) (f, u map[string]string, err error) {
+	n := m.running.Add(1)
+	defer m.running.Add(-1)
+	for {
+		peak := m.peak.Load()
+		if n <= peak || m.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+
+	if m.barrier != nil {
+		m.barrier.Done()
+		waitTimeout(m.barrier, time.Second)
+	}
+
+	time.Sleep(m.delay)
+	if m.err != nil {
+		return nil, nil, m.err
+	}
+
+	return m.mockParser.Provide(awaited, conv)
+}
+
+// waitTimeout waits for wg at most d.
+func waitTimeout(wg *sync.WaitGroup, d time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(d):
+	}
+}
+
+func Test_Concurrency(t *testing.T) {
+	var running, peak atomic.Int32
+	mock := func(name string, delay time.Duration, err error, values map[string]any) Provider {
+		return delayMock{newMock(values), name, delay, err, &running, &peak, nil}
+	}
+
+	// providers of the barrier are running at the same time only if they are fetched concurrently
+	var barrier sync.WaitGroup
+	barrier.Add(3)
+	waiting := func(name string, values map[string]any) Provider {
+		return delayMock{newMock(values), name, 0, nil, &running, &peak, &barrier}
+	}
+
+	tests := []struct {
+		name string
+		add  func(ps ...Provider) ParseOption
+		ps   []Provider
+		n    int
+		port int
+		peak int32
+		err  string
+	}{
+		{
+			name: "priority order",
+			add:  Concurrent,
+			ps: []Provider{
+				waiting("a", map[string]any{"port": 1}),
+				waiting("b", map[string]any{"port": 2, "name": "b"}),
+				waiting("c", map[string]any{"port": 3}),
+			},
+			port: 1,
+			peak: 3,
+		},
+		{
+			name: "bounded",
+			add:  Concurrent,
+			ps: []Provider{
+				mock("a", 20*time.Millisecond, nil, map[string]any{"port": 1}),
+				mock("b", 20*time.Millisecond, nil, map[string]any{}),
+				mock("c", 20*time.Millisecond, nil, map[string]any{}),
+				mock("d", 20*time.Millisecond, nil, map[string]any{}),
+			},
+			n:    2,
+			port: 1,
+			peak: 2,
+		},
+		{
+			name: "error by priority",
+			add:  Concurrent,
+			ps: []Provider{
+				mock("slow", 60*time.Millisecond, errors.New("slow failed"), nil),
+				mock("fast", 0, errors.New("fast failed"), nil),
+			},
+			n:   2,
+			err: `parse "slow": slow failed`,
+		},
+		{
+			name: "sequential",
+			add:  Providers,
+			ps: []Provider{
+				mock("a", 0, nil, map[string]any{"port": 1}),
+				mock("b", 0, nil, map[string]any{"port": 2}),
+			},
+			n:    2,
+			port: 1,
+			peak: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c = testConfig()
+			peak.Store(0)
+			port := Int("port", 0, "")
+			Str("name", "", "")
+
+			err := ParseWith(tt.add(tt.ps...), Concurrency(tt.n))
+			if tt.err != "" {
+				require.EqualError(t, err, tt.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.port, *port)
+			require.Equal(t, tt.peak, peak.Load())
+		})
+	}
+}
+
+// pathMock reads the value of an option set by higher priority providers.
+type pathMock struct {
+	path *string
+}
+
+func (m pathMock) Type() string {
+	return "path"
+}
+
+func (m pathMock) Provide(map[string]bool, func(any) string) (f, u map[string]string, err error) {
+	return map[string]string{"loaded": *m.path}, map[string]string{}, nil
+}
+
+func Test_ConcurrentStages(t *testing.T) {
+	c = testConfig()
+	c.parsers = []Provider{flag.New(flag.WithArgs([]string{"--config", "app.yaml"}))}
+
+	path := Str("config", "", "")
+	loaded := Str("loaded", "", "")
+	require.NoError(t, ParseWith(Concurrent(pathMock{path}, newMock(map[string]any{}))))
+	require.Equal(t, "app.yaml", *loaded)
+
+	// providers after a group read options set by it
+	c = testConfig()
+	path = Str("config", "", "")
+	loaded = Str("loaded", "", "")
+	require.NoError(t, ParseWith(
+		Concurrent(newMock(map[string]any{}), newMock(map[string]any{"config": "app.yaml"})),
+		Providers(pathMock{path}),
+		NoFlags(),
+	))
+	require.Equal(t, "app.yaml", *loaded)
+}
+
+type sourcerMock struct {
+	*mockParser
+	sources map[string]string
+}
+
+func (m sourcerMock) Source(key string) string {
+	return m.sources[key]
+}
+
+func Test_Sourcer(t *testing.T) {
+	c = testConfig()
+
+	Int("a", 0, "")
+	Int("b", 0, "")
+
+	err := Parse(sourcerMock{
+		mockParser: newMock(map[string]any{"a": 1, "b": 2}),
+		sources:    map[string]string{"a": "mock[file]"},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, "mock[file]", c.vs["a"].source())
+	require.Equal(t, mockType, c.vs["b"].source())
+}
+
+type inspectorMock struct {
+	*mockParser
+	options map[string]OptionInfo
+}
+
+func (m *inspectorMock) Inspect(options map[string]OptionInfo) {
+	m.options = options
+}
+
+func Test_Inspector(t *testing.T) {
+	c = testConfig()
+
+	Int("port", 0, "", Alias("p"))
+	Str("password", "", "", Secret())
+
+	m := &inspectorMock{mockParser: newMock(nil)}
+	require.NoError(t, Parse(m))
+
+	require.Equal(t, map[string]OptionInfo{
+		"port":     {Type: "int"},
+		"p":        {Type: "int"},
+		"password": {Type: "string", Secret: true},
+	}, m.options)
+}
+
 func Test_WrongType(t *testing.T) {
 	c = testConfig()
 
@@ -0,0 +1,237 @@
+package consul
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	nethttp "net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultAddress is the address of the local Consul agent.
+	DefaultAddress = "http://127.0.0.1:8500"
+	// DefaultTimeout is the default timeout of a non-blocking request.
+	DefaultTimeout = 10 * time.Second
+	// DefaultWait is the default duration of a single blocking query.
+	DefaultWait = 5 * time.Minute
+)
+
+type Opt func(*Provider)
+
+// WithAddress returns an Opt that sets the address of the Consul agent.
+// The address is read on Provide, so it can be set by other providers; an empty address means DefaultAddress.
+func WithAddress(address *string) Opt {
+	return func(p *Provider) {
+		p.address = address
+	}
+}
+
+// WithToken returns an Opt that sends the ACL token with every request. An empty token is not sent.
+func WithToken(token *string) Opt {
+	return func(p *Provider) {
+		p.token = token
+	}
+}
+
+// WithTimeout returns an Opt that limits the duration of a non-blocking request.
+func WithTimeout(d time.Duration) Opt {
+	return func(p *Provider) {
+		p.timeout = d
+	}
+}
+
+// WithWait returns an Opt that sets the duration of a single blocking query of Wait.
+func WithWait(d time.Duration) Opt {
+	return func(p *Provider) {
+		p.wait = d
+	}
+}
+
+// WithClient returns an Opt that sends requests with the client instead of http.DefaultClient.
+func WithClient(client *nethttp.Client) Opt {
+	return func(p *Provider) {
+		p.client = client
+	}
+}
+
+// Provider reads keys under a prefix of the Consul KV store.
+//
+// Keys are relative to the prefix and their "/" separators map to dots,
+// so "app/billing/db/host" read with the prefix "app/billing" is the option "db.host".
+// Values are used as is; folder entries are skipped.
+type Provider struct {
+	prefix string
+
+	address *string
+	token   *string
+	timeout time.Duration
+	wait    time.Duration
+	client  *nethttp.Client
+
+	// index is the X-Consul-Index of the last response.
+	index uint64
+}
+
+// New creates a new Provider reading the keys under prefix.
+func New(prefix string, opts ...Opt) *Provider {
+	p := &Provider{
+		prefix:  strings.Trim(prefix, "/"),
+		timeout: DefaultTimeout,
+		wait:    DefaultWait,
+		client:  nethttp.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// Type returns the type name of the parser.
+func (p *Provider) Type() string {
+	return fmt.Sprintf("consul[%s]", p.prefix)
+}
+
+// Index returns the X-Consul-Index of the last Provide or Wait.
+func (p *Provider) Index() uint64 {
+	return p.index
+}
+
+func (p *Provider) Provide(awaited map[string]bool, conv func(any) string) (found, unknown map[string]string, err error) {
+	return p.ProvideContext(context.Background(), awaited, conv)
+}
+
+// ProvideContext is Provide cancelled with ctx.
+func (p *Provider) ProvideContext(ctx context.Context, awaited map[string]bool, _ func(any) string) (found, unknown map[string]string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	pairs, index, err := p.list(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	p.index = index
+
+	found, unknown = make(map[string]string), make(map[string]string)
+	for _, pair := range pairs {
+		key, ok := p.key(pair.Key)
+		if !ok {
+			continue
+		}
+
+		if awaited[key] {
+			found[key] = string(pair.Value)
+		} else {
+			unknown[key] = string(pair.Value)
+		}
+	}
+
+	return found, unknown, nil
+}
+
+// Wait blocks until the keys under the prefix change after the last Provide or Wait,
+// using Consul blocking queries. It returns the error of ctx when it is done first.
+//
+// Usage:
+//
+//	for p.Wait(ctx) == nil {
+//		reload()
+//	}
+func (p *Provider) Wait(ctx context.Context) error {
+	for {
+		query := url.Values{
+			"index": {strconv.FormatUint(p.index, 10)},
+			"wait":  {fmt.Sprintf("%ds", int(p.wait.Seconds()))},
+		}
+
+		_, index, err := p.list(ctx, query)
+		if errors.Is(err, fs.ErrNotExist) {
+			// the prefix is empty, the index is still reported
+			err = nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if index != p.index {
+			p.index = index
+
+			return nil
+		}
+	}
+}
+
+// pair is an entry of the KV API response. Value is base64 encoded in JSON.
+type pair struct {
+	Key   string
+	Value []byte
+}
+
+// list reads all entries under the prefix and the index of the response.
+func (p *Provider) list(ctx context.Context, query url.Values) ([]pair, uint64, error) {
+	address := DefaultAddress
+	if p.address != nil && *p.address != "" {
+		address = *p.address
+	}
+
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("recurse", "true")
+
+	u := fmt.Sprintf("%s/v1/kv/%s?%s", strings.TrimRight(address, "/"), p.prefix, query.Encode())
+	req, err := nethttp.NewRequestWithContext(ctx, nethttp.MethodGet, u, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("consul %s: %w", p.prefix, err)
+	}
+
+	if p.token != nil && *p.token != "" {
+		req.Header.Set("X-Consul-Token", *p.token)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("consul %s: %w", p.prefix, err)
+	}
+	defer resp.Body.Close()
+
+	index, _ := strconv.ParseUint(resp.Header.Get("X-Consul-Index"), 10, 64)
+
+	switch resp.StatusCode {
+	case nethttp.StatusOK:
+	case nethttp.StatusNotFound:
+		return nil, index, fmt.Errorf("consul %s: no keys: %w", p.prefix, fs.ErrNotExist)
+	default:
+		return nil, 0, fmt.Errorf("consul %s: unexpected status %d %s", p.prefix, resp.StatusCode, nethttp.StatusText(resp.StatusCode))
+	}
+
+	var pairs []pair
+	if err = json.NewDecoder(resp.Body).Decode(&pairs); err != nil {
+		return nil, 0, fmt.Errorf("consul %s: decode response: %w", p.prefix, err)
+	}
+
+	return pairs, index, nil
+}
+
+// key converts a KV key to an option key. Folders and keys outside the prefix are skipped.
+func (p *Provider) key(kv string) (string, bool) {
+	if strings.HasSuffix(kv, "/") {
+		return "", false
+	}
+
+	if p.prefix != "" {
+		var ok bool
+		kv, ok = strings.CutPrefix(kv, p.prefix+"/")
+		if !ok {
+			return "", false
+		}
+	}
+
+	return strings.ReplaceAll(kv, "/", "."), true
+}
@@ -0,0 +1,180 @@
+package consul_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/fs"
+	nethttp "net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	zfg "github.com/chaindead/zerocfg"
+	"github.com/chaindead/zerocfg/consul"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeKV implements the subset of the Consul KV API used by the provider.
+type fakeKV struct {
+	mu      sync.Mutex
+	changed chan struct{}
+	index   uint64
+	kv      map[string]string
+	token   string
+}
+
+func newFakeKV(t *testing.T, kv map[string]string) (*fakeKV, string) {
+	f := &fakeKV{kv: kv, index: 1, changed: make(chan struct{})}
+
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+
+	return f, srv.URL
+}
+
+func (f *fakeKV) put(key, value string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.kv[key] = value
+	f.index++
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
+
+func (f *fakeKV) ServeHTTP(w nethttp.ResponseWriter, r *nethttp.Request) {
+	if f.token != "" && r.Header.Get("X-Consul-Token") != f.token {
+		w.WriteHeader(nethttp.StatusForbidden)
+
+		return
+	}
+
+	f.mu.Lock()
+	index, changed := f.index, f.changed
+	f.mu.Unlock()
+
+	if q := r.URL.Query().Get("index"); q != "" {
+		wait, _ := time.ParseDuration(r.URL.Query().Get("wait"))
+		if last, _ := strconv.ParseUint(q, 10, 64); last >= index {
+			select {
+			case <-changed:
+			case <-time.After(wait):
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	prefix := strings.TrimPrefix(r.URL.Path, "/v1/kv/")
+	type pair struct {
+		Key         string
+		Value       []byte
+		ModifyIndex uint64
+	}
+
+	var pairs []pair
+	for k, v := range f.kv {
+		if strings.HasPrefix(k, prefix) {
+			p := pair{Key: k, ModifyIndex: f.index}
+			if !strings.HasSuffix(k, "/") {
+				p.Value = []byte(v)
+			}
+			pairs = append(pairs, p)
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].Key < pairs[j].Key })
+
+	w.Header().Set("X-Consul-Index", strconv.FormatUint(f.index, 10))
+	if len(pairs) == 0 {
+		w.WriteHeader(nethttp.StatusNotFound)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(pairs)
+}
+
+func TestParse(t *testing.T) {
+	_, addr := newFakeKV(t, map[string]string{
+		"app/billing/":             "",
+		"app/billing/db/host":      "localhost",
+		"app/billing/db/port":      "5432",
+		"app/billing/feature/beta": "true",
+		"app/billing/limits/":      "",
+		"app/billing-old/db/host":  "old",
+		"app/other/db/host":        "other",
+		"app/billing/db/password":  "multi\nline",
+	})
+
+	p := consul.New("app/billing/", consul.WithAddress(&addr))
+	found, unknown, err := p.Provide(map[string]bool{
+		"db.host":     true,
+		"db.port":     true,
+		"db.password": true,
+	}, zfg.ToString)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"db.host":     "localhost",
+		"db.port":     "5432",
+		"db.password": "multi\nline",
+	}, found)
+	assert.Equal(t, map[string]string{"feature.beta": "true"}, unknown)
+	assert.Equal(t, uint64(1), p.Index())
+	assert.Equal(t, "consul[app/billing]", p.Type())
+}
+
+func TestToken(t *testing.T) {
+	f, addr := newFakeKV(t, map[string]string{"app/name": "billing"})
+	f.token = "secret"
+
+	token := ""
+	p := consul.New("app", consul.WithAddress(&addr), consul.WithToken(&token))
+	_, _, err := p.Provide(map[string]bool{}, zfg.ToString)
+	require.ErrorContains(t, err, "unexpected status 403 Forbidden")
+
+	token = "secret"
+	found, _, err := p.Provide(map[string]bool{"name": true}, zfg.ToString)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"name": "billing"}, found)
+}
+
+func TestNotFound(t *testing.T) {
+	_, addr := newFakeKV(t, map[string]string{})
+
+	_, _, err := consul.New("app", consul.WithAddress(&addr)).Provide(map[string]bool{}, zfg.ToString)
+	require.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestWait(t *testing.T) {
+	f, addr := newFakeKV(t, map[string]string{"app/name": "billing"})
+
+	p := consul.New("app", consul.WithAddress(&addr), consul.WithWait(time.Second))
+	_, _, err := p.Provide(map[string]bool{"name": true}, zfg.ToString)
+	require.NoError(t, err)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		f.put("app/name", "invoices")
+	}()
+
+	require.NoError(t, p.Wait(context.Background()))
+	assert.Equal(t, uint64(2), p.Index())
+
+	found, _, err := p.Provide(map[string]bool{"name": true}, zfg.ToString)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"name": "invoices"}, found)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, p.Wait(ctx), context.DeadlineExceeded)
+}
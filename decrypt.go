@@ -0,0 +1,52 @@
+package zerocfg
+
+import "fmt"
+
+// Decrypter decrypts values encrypted in place, such as ENC[...] values of the enc package.
+type Decrypter interface {
+	// Decrypt returns the plain value and true for an encrypted value, or false for other values.
+	Decrypt(value string) (plain string, ok bool, err error)
+}
+
+// Decrypt returns a ParseOption that decrypts the found values of all providers with d.
+// Values of options already set by a higher priority provider are not used, so they are
+// not decrypted. Options with decrypted values are hidden by Show as secrets until Reset.
+//
+// Usage:
+//
+//	zerocfg.ParseWith(zerocfg.Providers(yaml.New(path)), zerocfg.Decrypt(enc.FromEnv("APP_CONFIG_KEY")))
+func Decrypt(d Decrypter) ParseOption {
+	return func(o *parseOptions) {
+		o.decrypter = d
+	}
+}
+
+// decrypt replaces encrypted values of vs with plain values.
+// Values of options which already have a source are skipped, as they are not applied.
+func (c *config) decrypt(d Decrypter, vs map[string]string) error {
+	for k, v := range vs {
+		key := k
+		if trueKey, isAlias := c.aliases[k]; isAlias {
+			key = trueKey
+		}
+		n, exists := c.vs[key]
+		if exists && n.setSource != "" {
+			continue
+		}
+
+		plain, ok, err := d.Decrypt(v)
+		if err != nil {
+			return fmt.Errorf("key=%q: %w", k, err)
+		}
+		if !ok {
+			continue
+		}
+
+		vs[k] = plain
+		if exists {
+			n.isDecrypted = true
+		}
+	}
+
+	return nil
+}
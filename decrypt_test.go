@@ -0,0 +1,67 @@
+package zerocfg
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// reverseDecrypter decrypts values marked with "rev:" by reversing them.
+type reverseDecrypter struct{}
+
+func (reverseDecrypter) Decrypt(value string) (string, bool, error) {
+	v, ok := strings.CutPrefix(value, "rev:")
+	if !ok {
+		return "", false, nil
+	}
+	if v == "" {
+		return "", true, errors.New("empty value")
+	}
+
+	r := []rune(v)
+	for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+		r[i], r[j] = r[j], r[i]
+	}
+
+	return string(r), true, nil
+}
+
+func Test_Decrypt(t *testing.T) {
+	c = testConfig()
+	user := Str("db.user", "", "")
+	password := Str("db.password", "", "", Alias("p"))
+
+	err := ParseWith(Providers(newMock(map[string]any{"db.user": "admin", "p": "rev:ytrewq"})), Decrypt(reverseDecrypter{}))
+	require.NoError(t, err)
+	require.Equal(t, "admin", *user)
+	require.Equal(t, "qwerty", *password)
+	require.True(t, c.vs["db.password"].secret())
+	require.False(t, c.vs["db.user"].secret())
+	require.NotContains(t, Show(), "qwerty")
+
+	// a decrypted value is not a secret after Reset
+	Reset()
+	require.NoError(t, ParseWith(Providers(newMock(map[string]any{"p": "plain"})), Decrypt(reverseDecrypter{})))
+	require.False(t, c.vs["db.password"].secret())
+	require.Contains(t, Show(), "plain")
+
+	c = testConfig()
+	Str("db.password", "", "")
+
+	err = ParseWith(Providers(newMock(map[string]any{"db.password": "rev:"})), Decrypt(reverseDecrypter{}))
+	require.EqualError(t, err, `decrypt "mock": key="db.password": empty value`)
+
+	// values overridden by a higher priority provider are not decrypted
+	c = testConfig()
+	password = Str("db.password", "", "", Alias("p"))
+
+	err = ParseWith(Providers(
+		newMock(map[string]any{"db.password": "plain"}),
+		newMock(map[string]any{"p": "rev:"}),
+	), Decrypt(reverseDecrypter{}))
+	require.NoError(t, err)
+	require.Equal(t, "plain", *password)
+	require.False(t, c.vs["db.password"].secret())
+}
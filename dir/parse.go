@@ -0,0 +1,147 @@
+package dir
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/chaindead/zerocfg/util"
+)
+
+// DefaultMaxSize is the default limit for the size of a single file.
+const DefaultMaxSize = 1 << 20
+
+type Opt func(*Provider)
+
+// WithMaxSize returns an Opt that limits the size of a single file in bytes.
+// Larger files are reported as an error.
+func WithMaxSize(size int64) Opt {
+	return func(p *Provider) {
+		p.maxSize = size
+	}
+}
+
+// Provider reads a directory where every file is a single option, as Kubernetes
+// mounts ConfigMaps and Secrets. The file name is the option key and its trimmed
+// content is the value; nested directories are joined with dots, so both
+// "db.password" and "db/password" map to the key "db.password".
+//
+// Symlinks are followed and entries starting with ".." are skipped, which covers
+// the "..data" layout used by Kubernetes for atomic updates.
+type Provider struct {
+	path    *string
+	maxSize int64
+}
+
+// New creates a new Provider reading the directory at path.
+func New(path *string, opts ...Opt) *Provider {
+	p := &Provider{path: path, maxSize: DefaultMaxSize}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// Type returns the type name of the parser.
+func (p *Provider) Type() string {
+	return fmt.Sprintf("dir[%s]", util.ShortenPath(*p.path))
+}
+
+// Provide reads files matching the awaited keys. Other files are reported as unknown
+// with empty values, their content is never read.
+func (p *Provider) Provide(awaited map[string]bool, _ func(any) string) (found, unknown map[string]string, err error) {
+	found, unknown = make(map[string]string), make(map[string]string)
+
+	visited := make(map[string]bool)
+	err = p.walk(*p.path, "", awaited, visited, found, unknown)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return found, unknown, nil
+}
+
+func (p *Provider) walk(dir, prefix string, awaited, visited map[string]bool, found, unknown map[string]string) error {
+	resolved, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return fmt.Errorf("read dir: %w", err)
+	}
+
+	if visited[resolved] {
+		return nil
+	}
+	visited[resolved] = true
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("read dir: %w", err)
+	}
+
+	for _, e := range entries {
+		name := e.Name()
+		if strings.HasPrefix(name, "..") {
+			continue
+		}
+
+		path := filepath.Join(dir, name)
+		key := prefix + name
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return fmt.Errorf("stat %q: %w", path, err)
+		}
+
+		if info.IsDir() {
+			err = p.walk(path, key+".", awaited, visited, found, unknown)
+			if err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if !info.Mode().IsRegular() {
+			continue
+		}
+
+		if _, ok := awaited[key]; !ok {
+			unknown[key] = ""
+
+			continue
+		}
+
+		found[key], err = p.read(path, info.Size())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *Provider) read(path string, size int64) (string, error) {
+	if size > p.maxSize {
+		return "", fmt.Errorf("file %q exceeds size limit of %d bytes", path, p.maxSize)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("read file: %w", err)
+	}
+	defer f.Close()
+
+	// the file may grow after stat
+	data, err := io.ReadAll(io.LimitReader(f, p.maxSize+1))
+	if err != nil {
+		return "", fmt.Errorf("read file %q: %w", path, err)
+	}
+
+	if int64(len(data)) > p.maxSize {
+		return "", fmt.Errorf("file %q exceeds size limit of %d bytes", path, p.maxSize)
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
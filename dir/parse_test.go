@@ -0,0 +1,156 @@
+package dir_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	zfg "github.com/chaindead/zerocfg"
+	"github.com/chaindead/zerocfg/dir"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		awaited map[string]bool
+		found   map[string]string
+		unknown map[string]string
+	}{
+		{
+			name: "flat",
+			files: map[string]string{
+				"db.password": "secret\n",
+				"db.user":     "  admin  ",
+				"other":       "value",
+			},
+			awaited: map[string]bool{
+				"db.password": true,
+				"db.user":     true,
+			},
+			found: map[string]string{
+				"db.password": "secret",
+				"db.user":     "admin",
+			},
+			unknown: map[string]string{
+				"other": "",
+			},
+		},
+		{
+			name: "nested",
+			files: map[string]string{
+				"db/password":    "secret",
+				"db/pool/size":   "10",
+				"db/pool.unused": "1",
+			},
+			awaited: map[string]bool{
+				"db.password":  true,
+				"db.pool.size": true,
+			},
+			found: map[string]string{
+				"db.password":  "secret",
+				"db.pool.size": "10",
+			},
+			unknown: map[string]string{
+				"db.pool.unused": "",
+			},
+		},
+		{
+			name: "alias",
+			files: map[string]string{
+				"p": "5432",
+			},
+			awaited: map[string]bool{
+				"db.port": true,
+				"p":       false,
+			},
+			found: map[string]string{
+				"p": "5432",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.found == nil {
+				tt.found = map[string]string{}
+			}
+			if tt.unknown == nil {
+				tt.unknown = map[string]string{}
+			}
+
+			root := t.TempDir()
+			for name, data := range tt.files {
+				writeFile(t, filepath.Join(root, name), data)
+			}
+
+			p := dir.New(&root)
+
+			found, unknown, err := p.Provide(tt.awaited, zfg.ToString)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.found, found)
+			assert.Equal(t, tt.unknown, unknown)
+		})
+	}
+}
+
+func TestParse_KubernetesLayout(t *testing.T) {
+	root := t.TempDir()
+
+	// layout of a mounted volume after an atomic update
+	writeFile(t, filepath.Join(root, "..2024_01_01_00_00_00.1", "db.password"), "new")
+	writeFile(t, filepath.Join(root, "..2024_01_01_00_00_00.1", "tls", "cert"), "pem")
+	writeFile(t, filepath.Join(root, "..2023_01_01_00_00_00.1", "db.password"), "old")
+	require.NoError(t, os.Symlink("..2024_01_01_00_00_00.1", filepath.Join(root, "..data")))
+	require.NoError(t, os.Symlink(filepath.Join("..data", "db.password"), filepath.Join(root, "db.password")))
+	require.NoError(t, os.Symlink(filepath.Join("..data", "tls"), filepath.Join(root, "tls")))
+
+	p := dir.New(&root)
+
+	found, unknown, err := p.Provide(map[string]bool{"db.password": true, "tls.cert": true}, zfg.ToString)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"db.password": "new", "tls.cert": "pem"}, found)
+	assert.Empty(t, unknown)
+}
+
+func TestParse_SymlinkCycle(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "a", "key"), "value")
+	require.NoError(t, os.Symlink("..", filepath.Join(root, "a", "loop")))
+
+	p := dir.New(&root)
+
+	found, _, err := p.Provide(map[string]bool{"a.key": true}, zfg.ToString)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"a.key": "value"}, found)
+}
+
+func TestParse_Error(t *testing.T) {
+	t.Run("size limit", func(t *testing.T) {
+		root := t.TempDir()
+		writeFile(t, filepath.Join(root, "big"), "0123456789")
+
+		p := dir.New(&root, dir.WithMaxSize(5))
+
+		_, _, err := p.Provide(map[string]bool{"big": true}, zfg.ToString)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "exceeds size limit of 5 bytes")
+	})
+
+	t.Run("missing dir", func(t *testing.T) {
+		root := filepath.Join(t.TempDir(), "missing")
+		p := dir.New(&root)
+
+		_, _, err := p.Provide(map[string]bool{}, zfg.ToString)
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+func writeFile(t *testing.T, path, data string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+}
@@ -0,0 +1,111 @@
+package dotenv
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+
+	"github.com/chaindead/zerocfg/util"
+	denv "github.com/joho/godotenv"
+)
+
+type Opt func(*Provider)
+
+// WithPrefix returns an Opt that sets the prefix for variable names in the Provider.
+func WithPrefix(prefix string) Opt {
+	return func(p *Provider) {
+		p.prefix = prefix
+	}
+}
+
+// Provider reads variables from a .env file without touching the process environment.
+// Keys are mapped to variable names the same way as in the env provider (db.user -> DB_USER).
+type Provider struct {
+	src util.Source
+	// Prefix to prepend to all variable names.
+	prefix string
+}
+
+// New creates a new Provider reading the .env file at path.
+func New(path *string, opts ...Opt) *Provider {
+	return from(util.FileSource(path), opts)
+}
+
+// FromBytes creates a new Provider reading the given data.
+func FromBytes(data []byte, opts ...Opt) *Provider {
+	return from(util.BytesSource(data), opts)
+}
+
+// FromReader creates a new Provider reading r on the first Provide; its content is reused afterwards.
+func FromReader(r io.Reader, opts ...Opt) *Provider {
+	return from(util.ReaderSource(r), opts)
+}
+
+// FromFS creates a new Provider reading the named file of fsys, e.g. an embed.FS.
+func FromFS(fsys fs.FS, name string, opts ...Opt) *Provider {
+	return from(util.FSSource(fsys, name), opts)
+}
+
+func from(src util.Source, opts []Opt) *Provider {
+	p := &Provider{src: src}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// Type returns the type name of the parser.
+func (p *Provider) Type() string {
+	return fmt.Sprintf("dotenv[%s]", p.src.Name())
+}
+
+func (p *Provider) key(s string) string {
+	if p.prefix != "" {
+		return p.prefix + "." + s
+	}
+
+	return s
+}
+
+// Provide reads the file and returns values of the awaited keys.
+// Variables of the file not matching any awaited key are reported as unknown.
+func (p *Provider) Provide(awaited map[string]bool, _ func(any) string) (found, unknown map[string]string, err error) {
+	data, err := p.src.Load()
+	if err != nil {
+		return nil, nil, fmt.Errorf("read dotenv file: %w", err)
+	}
+
+	return p.parse(awaited, data)
+}
+
+func (p *Provider) parse(awaited map[string]bool, data []byte) (found, unknown map[string]string, err error) {
+	vars, err := denv.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, fmt.Errorf("parse dotenv: %w", err)
+	}
+
+	found = make(map[string]string)
+	used := make(map[string]bool, len(vars))
+	for k := range awaited {
+		name := util.EnvName(p.key(k))
+
+		v, ok := vars[name]
+		if !ok {
+			continue
+		}
+
+		found[k] = v
+		used[name] = true
+	}
+
+	unknown = make(map[string]string)
+	for name, v := range vars {
+		if !used[name] {
+			unknown[name] = v
+		}
+	}
+
+	return found, unknown, nil
+}
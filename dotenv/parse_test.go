@@ -0,0 +1,114 @@
+package dotenv_test
+
+import (
+	"os"
+	"testing"
+
+	zfg "github.com/chaindead/zerocfg"
+	"github.com/chaindead/zerocfg/dotenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		awaited map[string]bool
+		found   map[string]string
+		unknown map[string]string
+		opts    []dotenv.Opt
+	}{
+		{
+			name:    "simple",
+			input:   "ENV1=bar\nOTHER=baz",
+			awaited: map[string]bool{"env1": true},
+			found:   map[string]string{"env1": "bar"},
+			unknown: map[string]string{"OTHER": "baz"},
+		},
+		{
+			name: "composite",
+			input: `
+# comment
+export A_B_C_D="bar"
+CAMELCASE_DASH_UNDERWEAR='baz'`,
+			awaited: map[string]bool{
+				"a.b.c.d":                    true,
+				"camelCase.da-sh.under_wear": true,
+			},
+			found: map[string]string{
+				"a.b.c.d":                    "bar",
+				"camelCase.da-sh.under_wear": "baz",
+			},
+		},
+		{
+			name:    "alias",
+			input:   "P=5432",
+			awaited: map[string]bool{"db.port": true, "p": false},
+			found:   map[string]string{"p": "5432"},
+		},
+		{
+			name:    "prefix",
+			input:   "PREFIX_FOO=bar\nFOO=baz",
+			awaited: map[string]bool{"foo": true},
+			found:   map[string]string{"foo": "bar"},
+			unknown: map[string]string{"FOO": "baz"},
+			opts:    []dotenv.Opt{dotenv.WithPrefix("prefix")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.found == nil {
+				tt.found = map[string]string{}
+			}
+			if tt.unknown == nil {
+				tt.unknown = map[string]string{}
+			}
+
+			name := tempFile(t, tt.input)
+			p := dotenv.New(&name, tt.opts...)
+
+			found, unknown, err := p.Provide(tt.awaited, zfg.ToString)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.found, found)
+			assert.Equal(t, tt.unknown, unknown)
+		})
+	}
+}
+
+func TestParse_ProcessEnvUntouched(t *testing.T) {
+	const variable = "ZEROCFG_DOTENV_TEST"
+
+	name := tempFile(t, variable+"=value")
+	p := dotenv.New(&name)
+
+	found, _, err := p.Provide(map[string]bool{"zerocfg.dotenv.test": true}, zfg.ToString)
+	require.NoError(t, err)
+	require.Equal(t, "value", found["zerocfg.dotenv.test"])
+
+	_, ok := os.LookupEnv(variable)
+	require.False(t, ok)
+}
+
+func TestParse_Error(t *testing.T) {
+	name := "not-existing.env"
+	p := dotenv.New(&name)
+
+	_, _, err := p.Provide(map[string]bool{}, zfg.ToString)
+	assert.Error(t, err)
+}
+
+func tempFile(t *testing.T, data string) string {
+	f, err := os.CreateTemp("", "tmpfile-")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, f.Close())
+	})
+
+	_, err = f.WriteString(data)
+	require.NoError(t, err)
+
+	return f.Name()
+}
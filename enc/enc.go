@@ -0,0 +1,221 @@
+// Package enc encrypts configuration values in place, so files with secrets can be committed.
+//
+// An encrypted value looks like ENC[AES256_GCM,data:...,iv:...], where data is the
+// ciphertext with the GCM tag and iv is the nonce, both base64 encoded. Keys are 32 random
+// bytes, base64 encoded in key files and variables (see GenerateKey).
+package enc
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+const (
+	prefix    = "ENC["
+	suffix    = "]"
+	algorithm = "AES256_GCM"
+	keySize   = 32
+)
+
+var (
+	// ErrMalformed is returned for values marked as encrypted which cannot be parsed.
+	ErrMalformed = errors.New("malformed encrypted value")
+
+	// ErrNoKey is returned when the key file or variable is not set.
+	ErrNoKey = errors.New("no key")
+)
+
+// IsEncrypted reports whether value is marked as encrypted.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, prefix) && strings.HasSuffix(value, suffix)
+}
+
+// GenerateKey returns a new random key, base64 encoded for a key file or variable.
+func GenerateKey() (string, error) {
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// Key encrypts and decrypts values. The key is loaded on first use, so a missing key
+// is only an error when there are values to decrypt. A failed load is retried on next use.
+type Key struct {
+	name string
+	load func() ([]byte, error)
+
+	mu   sync.Mutex
+	aead cipher.AEAD
+}
+
+// FromBytes returns a Key of 32 raw bytes.
+func FromBytes(key []byte) *Key {
+	return &Key{name: "bytes", load: func() ([]byte, error) { return key, nil }}
+}
+
+// FromFile returns a Key read from the file at path, which holds the base64 encoded key.
+// The path is read on first use, so it can be set by flags or env.
+//
+// Usage:
+//
+//	keyPath := zfg.Str("config.key", "/etc/app/config.key", "path to the config key")
+//	zfg.ParseWith(zfg.Providers(yaml.New(path)), zfg.Decrypt(enc.FromFile(keyPath)))
+func FromFile(path *string) *Key {
+	return &Key{name: "file", load: func() ([]byte, error) {
+		if path == nil || *path == "" {
+			return nil, ErrNoKey
+		}
+
+		data, err := os.ReadFile(*path)
+		if err != nil {
+			return nil, err
+		}
+
+		return decodeKey(string(data))
+	}}
+}
+
+// FromEnv returns a Key read from the variable name, which holds the base64 encoded key.
+func FromEnv(name string) *Key {
+	return &Key{name: "env " + name, load: func() ([]byte, error) {
+		v, ok := os.LookupEnv(name)
+		if !ok || v == "" {
+			return nil, ErrNoKey
+		}
+
+		return decodeKey(v)
+	}}
+}
+
+func decodeKey(s string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, fmt.Errorf("decode key: %w", err)
+	}
+
+	return key, nil
+}
+
+// cipher returns the cipher of the key, loading it unless it was loaded before.
+func (k *Key) cipher() (cipher.AEAD, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.aead != nil {
+		return k.aead, nil
+	}
+
+	key, err := k.load()
+	if err != nil {
+		return nil, fmt.Errorf("load key from %s: %w", k.name, err)
+	}
+
+	if len(key) != keySize {
+		return nil, fmt.Errorf("load key from %s: key must be %d bytes, got %d", k.name, keySize, len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	k.aead = aead
+
+	return aead, nil
+}
+
+// Encrypt returns the encrypted form of value.
+func (k *Key) Encrypt(value string) (string, error) {
+	aead, err := k.cipher()
+	if err != nil {
+		return "", err
+	}
+
+	iv := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(iv); err != nil {
+		return "", err
+	}
+
+	data := aead.Seal(nil, iv, []byte(value), nil)
+
+	return fmt.Sprintf("%s%s,data:%s,iv:%s%s", prefix, algorithm,
+		base64.StdEncoding.EncodeToString(data), base64.StdEncoding.EncodeToString(iv), suffix), nil
+}
+
+// Decrypt returns the plain value and true for an encrypted value, or false for other values.
+// It implements zerocfg.Decrypter.
+func (k *Key) Decrypt(value string) (plain string, ok bool, err error) {
+	if !IsEncrypted(value) {
+		return "", false, nil
+	}
+
+	data, iv, err := parse(value)
+	if err != nil {
+		return "", true, err
+	}
+
+	aead, err := k.cipher()
+	if err != nil {
+		return "", true, err
+	}
+
+	if len(iv) != aead.NonceSize() {
+		return "", true, fmt.Errorf("%w: iv must be %d bytes", ErrMalformed, aead.NonceSize())
+	}
+
+	out, err := aead.Open(nil, iv, data, nil)
+	if err != nil {
+		return "", true, fmt.Errorf("decrypt: wrong key or corrupted value: %w", err)
+	}
+
+	return string(out), true, nil
+}
+
+// parse returns the ciphertext and the nonce of an encrypted value.
+func parse(value string) (data, iv []byte, err error) {
+	fields := strings.Split(strings.TrimSuffix(strings.TrimPrefix(value, prefix), suffix), ",")
+	if fields[0] != algorithm {
+		return nil, nil, fmt.Errorf("%w: unsupported algorithm %q", ErrMalformed, fields[0])
+	}
+
+	for _, field := range fields[1:] {
+		name, v, found := strings.Cut(field, ":")
+		if !found {
+			return nil, nil, fmt.Errorf("%w: field %q", ErrMalformed, field)
+		}
+
+		b, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %s: %w", ErrMalformed, name, err)
+		}
+
+		switch name {
+		case "data":
+			data = b
+		case "iv":
+			iv = b
+		default:
+			return nil, nil, fmt.Errorf("%w: unknown field %q", ErrMalformed, name)
+		}
+	}
+
+	if data == nil || iv == nil {
+		return nil, nil, fmt.Errorf("%w: data and iv are required", ErrMalformed)
+	}
+
+	return data, iv, nil
+}
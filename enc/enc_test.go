@@ -0,0 +1,108 @@
+package enc_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chaindead/zerocfg/enc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundTrip(t *testing.T) {
+	encoded, err := enc.GenerateKey()
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "config.key")
+	require.NoError(t, os.WriteFile(path, []byte(encoded+"\n"), 0o600))
+	t.Setenv("ZEROCFG_TEST_KEY", encoded)
+
+	keys := map[string]*enc.Key{
+		"file": enc.FromFile(&path),
+		"env":  enc.FromEnv("ZEROCFG_TEST_KEY"),
+	}
+
+	for name, key := range keys {
+		t.Run(name, func(t *testing.T) {
+			for _, value := range []string{"qwerty", "", "multi\nline ENC[]"} {
+				encrypted, err := key.Encrypt(value)
+				require.NoError(t, err)
+				assert.True(t, strings.HasPrefix(encrypted, "ENC[AES256_GCM,data:"))
+				assert.True(t, enc.IsEncrypted(encrypted))
+
+				plain, ok, err := enc.FromEnv("ZEROCFG_TEST_KEY").Decrypt(encrypted)
+				require.NoError(t, err)
+				assert.True(t, ok)
+				assert.Equal(t, value, plain)
+			}
+		})
+	}
+
+	plain, ok, err := keys["env"].Decrypt("qwerty")
+	require.NoError(t, err)
+	assert.False(t, ok)
+	assert.Empty(t, plain)
+}
+
+func TestDecrypt_Error(t *testing.T) {
+	key := enc.FromBytes(bytes.Repeat([]byte{1}, 32))
+	encrypted, err := key.Encrypt("qwerty")
+	require.NoError(t, err)
+
+	emptyPath := ""
+
+	tests := []struct {
+		name  string
+		key   *enc.Key
+		value string
+		err   string
+		is    error
+	}{
+		{name: "wrong key", key: enc.FromBytes(bytes.Repeat([]byte{2}, 32)), value: encrypted, err: "wrong key or corrupted value"},
+		{name: "short key", key: enc.FromBytes([]byte("short")), value: encrypted, err: "key must be 32 bytes, got 5"},
+		{name: "no env", key: enc.FromEnv("ZEROCFG_TEST_MISSING"), value: encrypted, is: enc.ErrNoKey},
+		{name: "no file", key: enc.FromFile(&emptyPath), value: encrypted, is: enc.ErrNoKey},
+		{name: "algorithm", key: key, value: "ENC[RSA,data:AA==,iv:AA==]", is: enc.ErrMalformed, err: `unsupported algorithm "RSA"`},
+		{name: "missing iv", key: key, value: "ENC[AES256_GCM,data:AA==]", is: enc.ErrMalformed},
+		{name: "bad base64", key: key, value: "ENC[AES256_GCM,data:!,iv:AA==]", is: enc.ErrMalformed},
+		{name: "bad iv", key: key, value: "ENC[AES256_GCM,data:AA==,iv:AA==]", is: enc.ErrMalformed, err: "iv must be 12 bytes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, ok, err := tt.key.Decrypt(tt.value)
+			assert.True(t, ok)
+			if tt.is != nil {
+				require.ErrorIs(t, err, tt.is)
+			}
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestKey_LoadRetry(t *testing.T) {
+	encoded, err := enc.GenerateKey()
+	require.NoError(t, err)
+
+	file := filepath.Join(t.TempDir(), "config.key")
+	require.NoError(t, os.WriteFile(file, []byte(encoded), 0o600))
+
+	var path string
+	key := enc.FromFile(&path)
+
+	encrypted, err := enc.FromFile(&file).Encrypt("qwerty")
+	require.NoError(t, err)
+
+	// the path is not set yet, e.g. by a provider applied later
+	_, _, err = key.Decrypt(encrypted)
+	require.ErrorIs(t, err, enc.ErrNoKey)
+
+	path = file
+	plain, ok, err := key.Decrypt(encrypted)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "qwerty", plain)
+}
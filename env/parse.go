@@ -2,14 +2,11 @@ package env
 
 import (
 	"os"
-	"regexp"
-	"strings"
 
+	"github.com/chaindead/zerocfg/util"
 	denv "github.com/joho/godotenv"
 )
 
-var cleanRe = regexp.MustCompile(`[^A-Za-z0-9.]+`)
-
 type Opt func(*Provider)
 
 // WithPrefix returns an Opt that sets the prefix for environment variable names in the Provider.
@@ -19,6 +16,9 @@ func WithPrefix(prefix string) Opt {
 	}
 }
 
+// WithPath returns an Opt that loads a .env file before reading environment variables.
+// The file is loaded into the process environment, so its variables are also visible
+// to child processes and later lookups. Use the dotenv provider to avoid this.
 func WithPath(path *string) Opt {
 	return func(p *Provider) {
 		p.path = path
@@ -66,7 +66,7 @@ func (p Provider) Provide(awaited map[string]bool, _ func(any) string) (found, u
 
 	keys := make(map[string]string, len(awaited))
 	for k := range awaited {
-		keys[k] = toENV(p.key(k))
+		keys[k] = util.EnvName(p.key(k))
 	}
 
 	found = make(map[string]string)
@@ -81,16 +81,3 @@ func (p Provider) Provide(awaited map[string]bool, _ func(any) string) (found, u
 
 	return found, unknown, nil
 }
-
-// toENV transforms the input string into an uppercase, underscore-separated
-// environment variable name by:
-// 1. Removing all characters except letters, digits, and dots.
-// 2. Converting to uppercase.
-// 3. Replacing dots with underscores.
-func toENV(s string) string {
-	cleaned := cleanRe.ReplaceAllString(s, "")
-	upper := strings.ToUpper(cleaned)
-	envName := strings.ReplaceAll(upper, ".", "_")
-
-	return envName
-}
@@ -24,6 +24,9 @@ var (
 
 	// ErrDoubleParse is returned when Parse is called more than once.
 	ErrDoubleParse = errors.New("misuse: Parse func should be called once")
+
+	// ErrStaleCache is returned by Cached when the provider fails and the cache is older than CacheMaxStale.
+	ErrStaleCache = errors.New("cache is stale")
 )
 
 // UnknownFieldError represents a mapping from configuration source names to unknown option keys encountered during parsing.
@@ -65,3 +68,48 @@ func (e *UnknownFieldError) add(source string, unknown map[string]string) {
 
 	(*e)[source] = s
 }
+
+// UnknownPositionError is returned by Parse instead of UnknownFieldError when providers
+// report where unknown keys are defined (see Locator). It unwraps to UnknownFieldError,
+// so IsUnknown keeps working.
+type UnknownPositionError struct {
+	UnknownFieldError
+
+	// Positions maps source names to unknown keys and their positions, e.g. "app.yaml:3:5".
+	Positions map[string]map[string]string
+}
+
+func (e *UnknownPositionError) Error() string {
+	located := make(map[string][]string, len(e.UnknownFieldError))
+	for source, keys := range e.UnknownFieldError {
+		for _, k := range keys {
+			if pos, ok := e.Positions[source][k]; ok {
+				k = k + " (" + pos + ")"
+			}
+
+			located[source] = append(located[source], k)
+		}
+	}
+
+	data, _ := json.Marshal(located)
+
+	return fmt.Sprintf("unknown fields: %s", string(data))
+}
+
+func (e *UnknownPositionError) Unwrap() error {
+	return e.UnknownFieldError
+}
+
+func addPositions(positions map[string]map[string]string, source string, unknown map[string]string, l Locator) {
+	for k := range unknown {
+		pos := l.Locate(k)
+		if pos == "" {
+			continue
+		}
+
+		if positions[source] == nil {
+			positions[source] = make(map[string]string)
+		}
+		positions[source][k] = pos
+	}
+}
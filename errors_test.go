@@ -37,3 +37,42 @@ func Test_NotUnknown(t *testing.T) {
 	_, ok := IsUnknown(io.ErrClosedPipe)
 	require.False(t, ok)
 }
+
+type locatorMock struct {
+	*mockParser
+	positions map[string]string
+}
+
+func (m locatorMock) Locate(key string) string {
+	return m.positions[key]
+}
+
+func Test_UnknownPositions(t *testing.T) {
+	c = testConfig()
+	Str("known", "", "")
+
+	err := Parse(locatorMock{
+		mockParser: newMock(map[string]any{"known": "field", "unknown": 1}),
+		positions:  map[string]string{"unknown": "app.yaml:3:5"},
+	})
+
+	u, ok := IsUnknown(err)
+	require.True(t, ok)
+	require.EqualValues(t, UnknownFieldError{mockType: []string{"unknown"}}, u)
+
+	var pErr *UnknownPositionError
+	require.ErrorAs(t, err, &pErr)
+	require.Equal(t, map[string]map[string]string{mockType: {"unknown": "app.yaml:3:5"}}, pErr.Positions)
+	require.Equal(t, `unknown fields: {"mock":["unknown (app.yaml:3:5)"]}`, err.Error())
+}
+
+func Test_SetErrorPosition(t *testing.T) {
+	c = testConfig()
+	Int("port", 0, "")
+
+	err := Parse(locatorMock{
+		mockParser: newMock(map[string]any{"port": "wrong"}),
+		positions:  map[string]string{"port": "app.yaml:1:7"},
+	})
+	require.ErrorContains(t, err, `set key="port" at app.yaml:1:7`)
+}
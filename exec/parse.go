@@ -0,0 +1,196 @@
+package exec
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	osexec "os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	zfg "github.com/chaindead/zerocfg"
+	"github.com/chaindead/zerocfg/dotenv"
+	"github.com/chaindead/zerocfg/json"
+	"github.com/chaindead/zerocfg/yaml"
+)
+
+// DefaultTimeout is the default time limit of the command.
+const DefaultTimeout = 10 * time.Second
+
+// maxStderr limits the length of stderr included in errors.
+const maxStderr = 4 << 10
+
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+	// FormatEnv is the .env format, keys map to variable names as in the env provider (db.user -> DB_USER).
+	FormatEnv = "env"
+)
+
+type Opt func(*Provider)
+
+// WithFormat returns an Opt that sets the format of the command output: FormatJSON (default), FormatYAML or FormatEnv.
+func WithFormat(format string) Opt {
+	return func(p *Provider) {
+		p.format = format
+	}
+}
+
+// WithTimeout returns an Opt that limits the run time of the command.
+func WithTimeout(d time.Duration) Opt {
+	return func(p *Provider) {
+		p.timeout = d
+	}
+}
+
+// WithEnv returns an Opt that adds "KEY=value" variables to the environment of the command.
+func WithEnv(env ...string) Opt {
+	return func(p *Provider) {
+		p.env = append(p.env, env...)
+	}
+}
+
+// InheritEnv returns an Opt that passes the named variables of the process environment to the command.
+func InheritEnv(names ...string) Opt {
+	return func(p *Provider) {
+		p.inherit = append(p.inherit, names...)
+	}
+}
+
+// WithDir returns an Opt that sets the working directory of the command.
+func WithDir(dir string) Opt {
+	return func(p *Provider) {
+		p.dir = dir
+	}
+}
+
+// Provider runs a command and reads its stdout, like credential helpers.
+//
+// The command gets a controlled environment: only PATH is inherited from the process,
+// other variables must be passed with InheritEnv or WithEnv. A failed command is
+// reported with its stderr. The output is data: YAML tags are rejected (see yaml.NoTags).
+type Provider struct {
+	name string
+	args []string
+
+	format  string
+	timeout time.Duration
+	env     []string
+	inherit []string
+	dir     string
+
+	options map[string]zfg.OptionInfo
+}
+
+// New creates a new Provider running the command with args.
+//
+// Usage:
+//
+//	exec.New("corp-creds", []string{"get", "db"}, exec.InheritEnv("HOME"))
+func New(name string, args []string, opts ...Opt) *Provider {
+	p := &Provider{
+		name:    name,
+		args:    args,
+		format:  FormatJSON,
+		timeout: DefaultTimeout,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// Type returns the type name of the parser. Arguments are omitted, as they may hold secrets.
+func (p *Provider) Type() string {
+	return fmt.Sprintf("exec[%s]", filepath.Base(p.name))
+}
+
+// Inspect receives the registered options, which are used to check YAML value structure.
+func (p *Provider) Inspect(options map[string]zfg.OptionInfo) {
+	p.options = options
+}
+
+func (p *Provider) Provide(keys map[string]bool, conv func(any) string) (found, unknown map[string]string, err error) {
+	return p.ProvideContext(context.Background(), keys, conv)
+}
+
+// ProvideContext is Provide with the command killed when ctx is done.
+func (p *Provider) ProvideContext(ctx context.Context, keys map[string]bool, conv func(any) string) (found, unknown map[string]string, err error) {
+	out, err := p.run(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch p.format {
+	case FormatJSON:
+		found, unknown, err = json.FromBytes(out).Provide(keys, conv)
+	case FormatYAML:
+		// the output is data: tags must not read local files or the environment
+		y := yaml.FromBytes(out, yaml.NoTags())
+		y.Inspect(p.options)
+		found, unknown, err = y.Provide(keys, conv)
+	case FormatEnv:
+		found, unknown, err = dotenv.FromBytes(out).Provide(keys, conv)
+	default:
+		return nil, nil, fmt.Errorf("exec %s: unsupported format %q", p.name, p.format)
+	}
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("exec %s: %w", p.name, err)
+	}
+
+	return found, unknown, nil
+}
+
+// run returns stdout of the command.
+func (p *Provider) run(parent context.Context) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(parent, p.timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := osexec.CommandContext(ctx, p.name, p.args...)
+	cmd.Env = p.environ()
+	cmd.Dir = p.dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	// do not wait for children holding the output open after the command was killed
+	cmd.WaitDelay = time.Second
+
+	err := cmd.Run()
+	switch {
+	case err == nil:
+	case parent.Err() != nil:
+		err = parent.Err()
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		err = fmt.Errorf("timed out after %s: %w", p.timeout, ctx.Err())
+	}
+	if err == nil {
+		return stdout.Bytes(), nil
+	}
+
+	msg := strings.TrimSpace(stderr.String())
+	if len(msg) > maxStderr {
+		msg = msg[:maxStderr] + "..."
+	}
+	if msg != "" {
+		return nil, fmt.Errorf("exec %s: %w: %s", p.name, err, msg)
+	}
+
+	return nil, fmt.Errorf("exec %s: %w", p.name, err)
+}
+
+func (p *Provider) environ() []string {
+	env := make([]string, 0, len(p.inherit)+len(p.env)+1)
+	for _, name := range append([]string{"PATH"}, p.inherit...) {
+		if v, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+v)
+		}
+	}
+
+	return append(env, p.env...)
+}
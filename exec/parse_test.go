@@ -0,0 +1,160 @@
+package exec_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	zfg "github.com/chaindead/zerocfg"
+	"github.com/chaindead/zerocfg/exec"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		script  string
+		opts    []exec.Opt
+		unknown string
+	}{
+		{
+			name:    "json",
+			script:  `echo '{"db": {"user": "'"$1"'", "password": "qwerty"}, "extra": 1}'`,
+			unknown: "extra",
+		},
+		{
+			name:    "yaml",
+			script:  `printf 'db:\n  user: %s\n  password: qwerty\nextra: 1\n' "$1"`,
+			opts:    []exec.Opt{exec.WithFormat(exec.FormatYAML)},
+			unknown: "extra",
+		},
+		{
+			name:    "env",
+			script:  `printf 'DB_USER=%s\nDB_PASSWORD=qwerty\nEXTRA=1\n' "$1"`,
+			opts:    []exec.Opt{exec.WithFormat(exec.FormatEnv)},
+			unknown: "EXTRA",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script := writeScript(t, tt.script)
+
+			p := exec.New(script, []string{"admin"}, tt.opts...)
+			found, unknown, err := p.Provide(map[string]bool{"db.user": true, "db.password": true}, zfg.ToString)
+			require.NoError(t, err)
+			assert.Equal(t, map[string]string{"db.user": "admin", "db.password": "qwerty"}, found)
+			assert.Equal(t, []string{tt.unknown}, keys(unknown))
+			assert.Equal(t, "exec[script.sh]", p.Type())
+		})
+	}
+}
+
+func TestEnv(t *testing.T) {
+	t.Setenv("ZEROCFG_EXEC_INHERITED", "inherited")
+	t.Setenv("ZEROCFG_EXEC_HIDDEN", "hidden")
+
+	script := writeScript(t, `printf 'INHERITED=%s\nHIDDEN=%s\nADDED=%s\nHAS_PATH=%s\n' "$ZEROCFG_EXEC_INHERITED" "$ZEROCFG_EXEC_HIDDEN" "$ZEROCFG_EXEC_ADDED" "${PATH:+yes}"`)
+
+	p := exec.New(script, nil,
+		exec.WithFormat(exec.FormatEnv),
+		exec.InheritEnv("ZEROCFG_EXEC_INHERITED"),
+		exec.WithEnv("ZEROCFG_EXEC_ADDED=added"),
+	)
+
+	found, _, err := p.Provide(map[string]bool{"inherited": true, "hidden": true, "added": true, "has.path": true}, zfg.ToString)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"inherited": "inherited",
+		"hidden":    "",
+		"added":     "added",
+		"has.path":  "yes",
+	}, found)
+}
+
+func TestParse_Error(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		opts   []exec.Opt
+		err    string
+	}{
+		{
+			name:   "exit code",
+			script: "echo 'credentials expired, run corp-login' >&2\nexit 3",
+			err:    "exit status 3: credentials expired, run corp-login",
+		},
+		{
+			name:   "timeout",
+			script: "sleep 5",
+			opts:   []exec.Opt{exec.WithTimeout(100 * time.Millisecond)},
+			err:    "timed out after 100ms",
+		},
+		{
+			name:   "invalid output",
+			script: "echo 'not json'",
+			err:    "unmarshal json",
+		},
+		{
+			name:   "yaml tags",
+			script: "echo 'password: !env HOME'",
+			opts:   []exec.Opt{exec.WithFormat(exec.FormatYAML)},
+			err:    "!env is not allowed, tags are disabled",
+		},
+		{
+			name:   "unsupported format",
+			script: "echo '{}'",
+			opts:   []exec.Opt{exec.WithFormat("xml")},
+			err:    `unsupported format "xml"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script := writeScript(t, tt.script)
+
+			_, _, err := exec.New(script, nil, tt.opts...).Provide(map[string]bool{}, zfg.ToString)
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+
+	_, _, err := exec.New(filepath.Join(t.TempDir(), "missing"), nil).Provide(map[string]bool{}, zfg.ToString)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestContext(t *testing.T) {
+	script := writeScript(t, "sleep 5")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, _, err := exec.New(script, nil).ProvideContext(ctx, map[string]bool{}, zfg.ToString)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.NotContains(t, err.Error(), "timed out after")
+	assert.Less(t, time.Since(start), 3*time.Second)
+}
+
+func writeScript(t *testing.T, body string) string {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell scripts are not supported on windows")
+	}
+
+	path := filepath.Join(t.TempDir(), "script.sh")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0o700))
+
+	return path
+}
+
+func keys(m map[string]string) []string {
+	ks := make([]string, 0, len(m))
+	for k := range m {
+		ks = append(ks, k)
+	}
+
+	return ks
+}
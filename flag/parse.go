@@ -5,18 +5,39 @@ import (
 	"strings"
 )
 
-type Provider struct{}
+type Opt func(*Provider)
 
-func New() Provider {
-	return Provider{}
+// WithArgs returns an Opt that parses args instead of os.Args[1:].
+func WithArgs(args []string) Opt {
+	return func(p *Provider) {
+		p.args = args
+		p.hasArgs = true
+	}
+}
+
+type Provider struct {
+	args    []string
+	hasArgs bool
+}
+
+func New(opts ...Opt) Provider {
+	p := Provider{}
+	for _, opt := range opts {
+		opt(&p)
+	}
+
+	return p
 }
 
 func (Provider) Type() string {
 	return "flag"
 }
 
-func (Provider) Provide(awaited map[string]bool, _ func(any) string) (found, unknown map[string]string, err error) {
-	args := os.Args[1:]
+func (p Provider) Provide(awaited map[string]bool, _ func(any) string) (found, unknown map[string]string, err error) {
+	args := p.args
+	if !p.hasArgs {
+		args = os.Args[1:]
+	}
 
 	found, unknown = parse(awaited, args)
 	return
@@ -100,3 +100,16 @@ func TestParse(t *testing.T) {
 		})
 	}
 }
+
+func TestWithArgs(t *testing.T) {
+	os.Args = []string{"program", "--port", "1"}
+
+	found, unknown, err := flag.New(flag.WithArgs([]string{"--port", "2", "-v"})).Provide(map[string]bool{"port": true}, zfg.ToString)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"port": "2"}, found)
+	assert.Equal(t, map[string]string{"v": ""}, unknown)
+
+	found, _, err = flag.New(flag.WithArgs(nil)).Provide(map[string]bool{"port": true}, zfg.ToString)
+	require.NoError(t, err)
+	assert.Empty(t, found)
+}
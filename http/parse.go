@@ -0,0 +1,288 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	nethttp "net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	zfg "github.com/chaindead/zerocfg"
+	"github.com/chaindead/zerocfg/json"
+	"github.com/chaindead/zerocfg/yaml"
+)
+
+// DefaultTimeout is the default timeout of a single request.
+const DefaultTimeout = 10 * time.Second
+
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+)
+
+type Opt func(*Provider)
+
+// WithBearer returns an Opt that sends the token in the Authorization header.
+// The token is read on Provide, so it can be set by other providers; an empty token is not sent.
+func WithBearer(token *string) Opt {
+	return func(p *Provider) {
+		p.bearer = token
+	}
+}
+
+// WithBasicAuth returns an Opt that authenticates requests with the user and password.
+// Both are read on Provide; credentials are not sent while the user is empty.
+func WithBasicAuth(user, password *string) Opt {
+	return func(p *Provider) {
+		p.user = user
+		p.password = password
+	}
+}
+
+// WithTimeout returns an Opt that limits the duration of a single request.
+func WithTimeout(d time.Duration) Opt {
+	return func(p *Provider) {
+		p.timeout = d
+	}
+}
+
+// WithRetries returns an Opt that retries failed requests up to n times, waiting delay between attempts.
+// Network errors, 429 and 5xx responses are retried.
+func WithRetries(n int, delay time.Duration) Opt {
+	return func(p *Provider) {
+		p.retries = n
+		p.delay = delay
+	}
+}
+
+// WithFormat returns an Opt that decodes the response as FormatJSON or FormatYAML
+// regardless of its content type.
+func WithFormat(format string) Opt {
+	return func(p *Provider) {
+		p.format = format
+	}
+}
+
+// WithClient returns an Opt that sends requests with the client instead of http.DefaultClient.
+func WithClient(client *nethttp.Client) Opt {
+	return func(p *Provider) {
+		p.client = client
+	}
+}
+
+// StatusError is returned for responses with an unexpected status code.
+// A 404 response matches fs.ErrNotExist.
+type StatusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("GET %s: unexpected status %d %s", e.URL, e.StatusCode, nethttp.StatusText(e.StatusCode))
+}
+
+func (e *StatusError) Is(target error) bool {
+	return target == fs.ErrNotExist && e.StatusCode == nethttp.StatusNotFound
+}
+
+// Provider fetches a JSON or YAML document with a GET request.
+//
+// The format is chosen by the Content-Type of the response, falling back to the
+// extension of the URL path. Responses with an ETag are cached, later requests
+// send If-None-Match and reuse the cached document on 304 Not Modified.
+// YAML tags are rejected (see yaml.NoTags), so a document cannot read local files or variables.
+type Provider struct {
+	url *string
+
+	bearer   *string
+	user     *string
+	password *string
+	timeout  time.Duration
+	retries  int
+	delay    time.Duration
+	format   string
+	client   *nethttp.Client
+
+	options map[string]zfg.OptionInfo
+
+	// etag and cached hold the last document with an ETag.
+	etag   string
+	cached []byte
+	// cachedFormat is the format of the cached document.
+	cachedFormat string
+}
+
+// New creates a new Provider fetching the document at rawURL.
+// The URL is read on Provide, so it can be set by other providers.
+func New(rawURL *string, opts ...Opt) *Provider {
+	p := &Provider{
+		url:     rawURL,
+		timeout: DefaultTimeout,
+		client:  nethttp.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// Type returns the type name of the parser. Passwords in the URL are redacted.
+func (p *Provider) Type() string {
+	return fmt.Sprintf("http[%s]", redact(*p.url))
+}
+
+// Inspect receives the registered options, which are used to check YAML value structure.
+func (p *Provider) Inspect(options map[string]zfg.OptionInfo) {
+	p.options = options
+}
+
+func (p *Provider) Provide(keys map[string]bool, conv func(any) string) (found, unknown map[string]string, err error) {
+	return p.ProvideContext(context.Background(), keys, conv)
+}
+
+// ProvideContext is Provide cancelled with ctx, including the delays between retries.
+func (p *Provider) ProvideContext(ctx context.Context, keys map[string]bool, conv func(any) string) (found, unknown map[string]string, err error) {
+	data, format, err := p.fetch(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch format {
+	case FormatJSON:
+		found, unknown, err = json.FromBytes(data).Provide(keys, conv)
+	case FormatYAML:
+		// tags of a remote document must not read local files or the environment
+		y := yaml.FromBytes(data, yaml.NoTags())
+		y.Inspect(p.options)
+		found, unknown, err = y.Provide(keys, conv)
+	default:
+		return nil, nil, fmt.Errorf("GET %s: unsupported format %q", redact(*p.url), format)
+	}
+
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode %s: %w", redact(*p.url), err)
+	}
+
+	return found, unknown, nil
+}
+
+// fetch returns the document and its format, retrying failed requests.
+func (p *Provider) fetch(ctx context.Context) ([]byte, string, error) {
+	for attempt := 0; ; attempt++ {
+		data, format, retry, err := p.get(ctx)
+		if err == nil || !retry || attempt >= p.retries || ctx.Err() != nil {
+			return data, format, err
+		}
+
+		timer := time.NewTimer(p.delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+
+			return nil, "", fmt.Errorf("%w, last attempt: %w", ctx.Err(), err)
+		}
+	}
+}
+
+func (p *Provider) get(ctx context.Context) (data []byte, format string, retry bool, err error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	req, err := nethttp.NewRequestWithContext(ctx, nethttp.MethodGet, *p.url, nil)
+	if err != nil {
+		return nil, "", false, fmt.Errorf("GET %s: %w", redact(*p.url), err)
+	}
+
+	req.Header.Set("Accept", "application/json, application/yaml;q=0.9")
+	if p.etag != "" {
+		req.Header.Set("If-None-Match", p.etag)
+	}
+	if p.bearer != nil && *p.bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+*p.bearer)
+	}
+	if p.user != nil && *p.user != "" {
+		var password string
+		if p.password != nil {
+			password = *p.password
+		}
+
+		req.SetBasicAuth(*p.user, password)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		// drop the url.Error wrapper, which repeats the unredacted URL
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+
+		return nil, "", true, fmt.Errorf("GET %s: %w", redact(*p.url), err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == nethttp.StatusNotModified && p.etag != "":
+		return p.cached, p.cachedFormat, false, nil
+	case resp.StatusCode != nethttp.StatusOK:
+		retry = resp.StatusCode == nethttp.StatusTooManyRequests || resp.StatusCode >= 500
+
+		return nil, "", retry, &StatusError{URL: redact(*p.url), StatusCode: resp.StatusCode}
+	}
+
+	data, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", true, fmt.Errorf("GET %s: read body: %w", redact(*p.url), err)
+	}
+
+	format = p.format
+	if format == "" {
+		format = detect(resp.Header.Get("Content-Type"), req.URL.Path)
+	}
+	if format == "" {
+		return nil, "", false, fmt.Errorf("GET %s: unsupported content type %q", redact(*p.url), resp.Header.Get("Content-Type"))
+	}
+
+	p.etag, p.cached, p.cachedFormat = resp.Header.Get("ETag"), data, format
+
+	return data, format, false, nil
+}
+
+// detect returns the format of a document by its content type or the extension of the URL path,
+// or an empty string if neither is known.
+func detect(contentType, urlPath string) string {
+	media, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case media == "application/json" || strings.HasSuffix(media, "+json"):
+		return FormatJSON
+	case media == "application/yaml" || media == "application/x-yaml" ||
+		media == "text/yaml" || media == "text/x-yaml" || strings.HasSuffix(media, "+yaml"):
+		return FormatYAML
+	}
+
+	switch path.Ext(urlPath) {
+	case ".json":
+		return FormatJSON
+	case ".yaml", ".yml":
+		return FormatYAML
+	}
+
+	return ""
+}
+
+func redact(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+
+	return u.Redacted()
+}
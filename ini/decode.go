@@ -0,0 +1,204 @@
+package ini
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// decode parses INI data into nested maps. Section names and keys are split
+// by dots, so "[db]" with "pool.size = 10" maps to the key "db.pool.size".
+//
+// Supported syntax:
+//   - comment lines starting with ';' or '#'
+//   - inline comments after unquoted values, separated by whitespace
+//   - "key = value" and "key: value" pairs
+//   - double-quoted values with escape sequences and single-quoted literal values
+//   - escaped backslashes, quotes and comment characters in unquoted values
+//   - line continuation with a trailing backslash
+func decode(data []byte) (map[string]any, error) {
+	settings := make(map[string]any)
+	seen := make(map[string]bool)
+
+	var section string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		start := n
+		line := strings.TrimSpace(scanner.Text())
+
+		// comments are not continued, so a trailing backslash in them is kept
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+
+		for strings.HasSuffix(line, `\`) && !strings.HasSuffix(line, `\\`) && scanner.Scan() {
+			n++
+			line = line[:len(line)-1] + strings.TrimSpace(scanner.Text())
+		}
+
+		if line[0] == '[' {
+			end := strings.IndexByte(line, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("line %d: unterminated section header", start)
+			}
+
+			rest := strings.TrimSpace(line[end+1:])
+			if rest != "" && rest[0] != ';' && rest[0] != '#' {
+				return nil, fmt.Errorf("line %d: unexpected %q after section header", start, rest)
+			}
+
+			section = strings.TrimSpace(line[1:end])
+			continue
+		}
+
+		sep := strings.IndexAny(line, "=:")
+		if sep < 0 {
+			return nil, fmt.Errorf("line %d: expected '=' or ':' after key", start)
+		}
+
+		key := strings.TrimSpace(line[:sep])
+		if key == "" {
+			return nil, fmt.Errorf("line %d: empty key", start)
+		}
+
+		value, err := parseValue(strings.TrimSpace(line[sep+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", start, err)
+		}
+
+		if section != "" {
+			key = section + "." + key
+		}
+
+		if seen[key] {
+			return nil, fmt.Errorf("line %d: duplicate key %q", start, key)
+		}
+		seen[key] = true
+
+		if err = setPath(settings, key, value); err != nil {
+			return nil, fmt.Errorf("line %d: %w", start, err)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+func parseValue(s string) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+
+	switch s[0] {
+	case '"':
+		return quoted(s)
+	case '\'':
+		end := strings.IndexByte(s[1:], '\'')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated quoted value")
+		}
+
+		if err := trailing(s[end+2:]); err != nil {
+			return "", err
+		}
+
+		return s[1 : end+1], nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case ch == '\\' && i+1 < len(s) && strings.IndexByte(`\;#"'`, s[i+1]) >= 0:
+			i++
+			b.WriteByte(s[i])
+		case (ch == ';' || ch == '#') && i > 0 && (s[i-1] == ' ' || s[i-1] == '\t'):
+			return strings.TrimSpace(b.String()), nil
+		default:
+			b.WriteByte(ch)
+		}
+	}
+
+	return b.String(), nil
+}
+
+func quoted(s string) (string, error) {
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case ch == '"':
+			if err := trailing(s[i+1:]); err != nil {
+				return "", err
+			}
+
+			return b.String(), nil
+		case ch == '\\' && i+1 < len(s):
+			i++
+			b.WriteString(unescape(s[i]))
+		default:
+			b.WriteByte(ch)
+		}
+	}
+
+	return "", fmt.Errorf("unterminated quoted value")
+}
+
+// trailing checks that only a comment follows a quoted value.
+func trailing(s string) error {
+	s = strings.TrimSpace(s)
+	if s != "" && s[0] != ';' && s[0] != '#' {
+		return fmt.Errorf("unexpected %q after quoted value", s)
+	}
+
+	return nil
+}
+
+// unescape returns the character for an escape sequence in a double-quoted value.
+// Unknown sequences are kept as is.
+func unescape(ch byte) string {
+	switch ch {
+	case 'n':
+		return "\n"
+	case 't':
+		return "\t"
+	case 'r':
+		return "\r"
+	case '0':
+		return "\x00"
+	case '\\', '"', '\'', ';', '#', '=', ':':
+		return string(ch)
+	default:
+		return `\` + string(ch)
+	}
+}
+
+// setPath stores the value under the dotted key, creating nested maps.
+func setPath(m map[string]any, key, value string) error {
+	parts := strings.Split(key, ".")
+	for i, part := range parts[:len(parts)-1] {
+		switch next := m[part].(type) {
+		case nil:
+			sub := make(map[string]any)
+			m[part] = sub
+			m = sub
+		case map[string]any:
+			m = next
+		default:
+			return fmt.Errorf("key %q conflicts with value of %q", key, strings.Join(parts[:i+1], "."))
+		}
+	}
+
+	last := parts[len(parts)-1]
+	if _, ok := m[last].(map[string]any); ok {
+		return fmt.Errorf("key %q conflicts with nested keys", key)
+	}
+
+	m[last] = value
+
+	return nil
+}
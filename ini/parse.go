@@ -0,0 +1,93 @@
+package ini
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+
+	"github.com/chaindead/zerocfg/util"
+)
+
+type Provider struct {
+	src util.Source
+
+	conv    func(any) string
+	awaited map[string]bool
+}
+
+func New(path *string) *Provider {
+	return &Provider{src: util.FileSource(path)}
+}
+
+// FromBytes creates a Provider reading the given data.
+func FromBytes(data []byte) *Provider {
+	return &Provider{src: util.BytesSource(data)}
+}
+
+// FromReader creates a Provider reading r on the first Provide; its content is reused afterwards.
+func FromReader(r io.Reader) *Provider {
+	return &Provider{src: util.ReaderSource(r)}
+}
+
+// FromFS creates a Provider reading the named file of fsys, e.g. an embed.FS.
+func FromFS(fsys fs.FS, name string) *Provider {
+	return &Provider{src: util.FSSource(fsys, name)}
+}
+
+func (p *Provider) Type() string {
+	return fmt.Sprintf("ini[%s]", p.src.Name())
+}
+
+func (p *Provider) Provide(keys map[string]bool, conv func(any) string) (found, unknown map[string]string, err error) {
+	data, err := p.src.Load()
+	if err != nil {
+		return nil, nil, fmt.Errorf("read ini file: %w", err)
+	}
+
+	p.conv = conv
+	p.awaited = keys
+
+	return p.parse(data)
+}
+
+func (p *Provider) parse(data []byte) (found, unknown map[string]string, err error) {
+	settings, err := decode(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unmarshal ini: %w", err)
+	}
+
+	found, unknown = p.flatten(settings)
+
+	return found, unknown, nil
+}
+
+func (p *Provider) flatten(settings map[string]any) (found, unknown map[string]string) {
+	found, unknown = make(map[string]string), make(map[string]string)
+
+	p.flattenDFS(settings, "", found, unknown)
+
+	return found, unknown
+}
+
+func (p *Provider) flattenDFS(m map[string]any, prefix string, found, unknown map[string]string) {
+	for k, v := range m {
+		newKey := k
+		if prefix != "" {
+			newKey = prefix + "." + k
+		}
+
+		if p.awaited[newKey] {
+			found[newKey] = p.conv(v)
+
+			continue
+		}
+
+		if subMap, ok := v.(map[string]any); ok {
+			p.flattenDFS(subMap, newKey, found, unknown)
+
+			continue
+		}
+
+		unknown[newKey] = p.conv(v)
+	}
+}
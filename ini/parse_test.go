@@ -0,0 +1,201 @@
+package ini_test
+
+import (
+	"os"
+	"testing"
+
+	zfg "github.com/chaindead/zerocfg"
+	"github.com/chaindead/zerocfg/ini"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		awaited map[string]bool
+		found   map[string]string
+		unknown map[string]string
+	}{
+		{
+			name: "global keys",
+			input: `
+; comment
+str = name
+# comment
+int: 1`,
+			awaited: map[string]bool{
+				"str": true,
+			},
+			found: map[string]string{
+				"str": `name`,
+			},
+			unknown: map[string]string{
+				"int": `1`,
+			},
+		},
+		{
+			name: "sections",
+			input: `
+host = localhost
+
+[database]
+port = 5432
+
+[database.credentials]
+username = admin`,
+			awaited: map[string]bool{
+				"database.credentials.username": true,
+			},
+			found: map[string]string{
+				"database.credentials.username": `admin`,
+			},
+			unknown: map[string]string{
+				"host":          `localhost`,
+				"database.port": `5432`,
+			},
+		},
+		{
+			name: "section as map",
+			input: `
+[limits]
+max = 10
+min = 1`,
+			awaited: map[string]bool{
+				"limits": true,
+			},
+			found: map[string]string{
+				"limits": `{"max":"10","min":"1"}`,
+			},
+		},
+		{
+			name: "values",
+			input: `
+inline = value ; comment
+hash = a#b
+quoted = "tab\t \"q\" ; not a comment"
+literal = 'C:\path\n' # comment
+path = C:\Users\name
+escaped = a \; b
+continued = first \
+    second
+empty =`,
+			awaited: map[string]bool{
+				"inline":    true,
+				"hash":      true,
+				"quoted":    true,
+				"literal":   true,
+				"path":      true,
+				"escaped":   true,
+				"continued": true,
+				"empty":     true,
+			},
+			found: map[string]string{
+				"inline":    `value`,
+				"hash":      `a#b`,
+				"quoted":    "tab\t \"q\" ; not a comment",
+				"literal":   `C:\path\n`,
+				"path":      `C:\Users\name`,
+				"escaped":   `a ; b`,
+				"continued": `first second`,
+				"empty":     ``,
+			},
+		},
+		{
+			name: "comment with trailing backslash",
+			input: `
+; old path C:\
+name = x
+# continued \
+[db]
+port = 1`,
+			awaited: map[string]bool{
+				"name":    true,
+				"db.port": true,
+			},
+			found: map[string]string{
+				"name":    `x`,
+				"db.port": `1`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.found == nil {
+				tt.found = map[string]string{}
+			}
+			if tt.unknown == nil {
+				tt.unknown = map[string]string{}
+			}
+
+			name := tempFile(t, tt.input)
+			p := ini.New(&name)
+
+			found, unknown, err := p.Provide(tt.awaited, zfg.ToString)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.found, found)
+			assert.Equal(t, tt.unknown, unknown)
+		})
+	}
+}
+
+func TestParse_Error(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{
+			name:  "duplicate key",
+			input: "[a]\nb = 1\n[a]\nb = 2",
+			err:   `line 4: duplicate key "a.b"`,
+		},
+		{
+			name:  "missing separator",
+			input: "key",
+			err:   `line 1: expected '=' or ':' after key`,
+		},
+		{
+			name:  "unterminated section",
+			input: "\n[a",
+			err:   `line 2: unterminated section header`,
+		},
+		{
+			name:  "unterminated quote",
+			input: `a = "value`,
+			err:   `line 1: unterminated quoted value`,
+		},
+		{
+			name:  "key conflict",
+			input: "a = 1\n[a]\nb = 2",
+			err:   `line 3: key "a.b" conflicts with value of "a"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := tempFile(t, tt.input)
+			p := ini.New(&name)
+
+			_, _, err := p.Provide(map[string]bool{}, zfg.ToString)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
+
+func tempFile(t *testing.T, data string) string {
+	f, err := os.CreateTemp("", "tmpfile-")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, f.Close())
+	})
+
+	_, err = f.WriteString(data)
+	require.NoError(t, err)
+
+	return f.Name()
+}
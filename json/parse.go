@@ -0,0 +1,292 @@
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strconv"
+	"strings"
+
+	"github.com/chaindead/zerocfg/util"
+)
+
+type Provider struct {
+	src util.Source
+
+	conv    func(any) string
+	awaited map[string]bool
+}
+
+func New(path *string) *Provider {
+	return &Provider{src: util.FileSource(path)}
+}
+
+// FromBytes creates a Provider reading the given data.
+func FromBytes(data []byte) *Provider {
+	return &Provider{src: util.BytesSource(data)}
+}
+
+// FromReader creates a Provider reading r on the first Provide; its content is reused afterwards.
+func FromReader(r io.Reader) *Provider {
+	return &Provider{src: util.ReaderSource(r)}
+}
+
+// FromFS creates a Provider reading the named file of fsys, e.g. an embed.FS.
+func FromFS(fsys fs.FS, name string) *Provider {
+	return &Provider{src: util.FSSource(fsys, name)}
+}
+
+func (p *Provider) Type() string {
+	return fmt.Sprintf("json[%s]", p.src.Name())
+}
+
+func (p *Provider) Provide(keys map[string]bool, conv func(any) string) (found, unknown map[string]string, err error) {
+	data, err := p.src.Load()
+	if err != nil {
+		return nil, nil, fmt.Errorf("read json file: %w", err)
+	}
+
+	p.conv = conv
+	p.awaited = keys
+
+	return p.parse(data)
+}
+
+func (p *Provider) parse(data []byte) (found, unknown map[string]string, err error) {
+	settings, err := decode(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unmarshal json: %w", err)
+	}
+
+	found, unknown = p.flatten(settings)
+
+	return found, unknown, nil
+}
+
+func (p *Provider) flatten(settings map[string]any) (found, unknown map[string]string) {
+	found, unknown = make(map[string]string), make(map[string]string)
+
+	p.flattenDFS(settings, "", found, unknown)
+
+	return found, unknown
+}
+
+func (p *Provider) flattenDFS(m map[string]any, prefix string, found, unknown map[string]string) {
+	for k, v := range m {
+		newKey := k
+		if prefix != "" {
+			newKey = prefix + "." + k
+		}
+
+		// null leaves the option unset
+		if v == nil {
+			continue
+		}
+
+		if p.awaited[newKey] {
+			found[newKey] = p.conv(v)
+
+			continue
+		}
+
+		if subMap, ok := v.(map[string]any); ok {
+			p.flattenDFS(subMap, newKey, found, unknown)
+
+			continue
+		}
+
+		unknown[newKey] = p.conv(v)
+	}
+}
+
+// decoder reads a JSON document token by token, so that duplicate keys can be
+// rejected and every error can be reported with its line and column.
+type decoder struct {
+	data []byte
+	dec  *json.Decoder
+}
+
+func decode(data []byte) (map[string]any, error) {
+	d := &decoder{
+		data: data,
+		dec:  json.NewDecoder(bytes.NewReader(data)),
+	}
+	d.dec.UseNumber()
+
+	start := d.start()
+	tok, err := d.token()
+	if err != nil {
+		return nil, err
+	}
+
+	if tok != json.Delim('{') {
+		return nil, d.errorf(start, "top-level value must be an object")
+	}
+
+	settings, err := d.object()
+	if err != nil {
+		return nil, err
+	}
+
+	start = d.start()
+	if _, err = d.dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, d.errorf(start, "unexpected data after top-level object")
+	}
+
+	return settings, nil
+}
+
+func (d *decoder) token() (json.Token, error) {
+	tok, err := d.dec.Token()
+	if err == nil {
+		return tok, nil
+	}
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		// Offset points past the offending byte, unless the input ended
+		offset := syntaxErr.Offset - 1
+		if syntaxErr.Offset >= int64(len(d.data)) {
+			offset = int64(len(d.data))
+		}
+
+		return nil, d.wrap(offset, err)
+	}
+
+	if errors.Is(err, io.EOF) {
+		return nil, d.wrap(int64(len(d.data)), io.ErrUnexpectedEOF)
+	}
+
+	return nil, d.wrap(d.dec.InputOffset(), err)
+}
+
+func (d *decoder) value() (any, error) {
+	tok, err := d.token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := tok.(type) {
+	case json.Delim:
+		if v == '{' {
+			return d.object()
+		}
+
+		return d.array()
+	case json.Number:
+		return number(v), nil
+	default:
+		return v, nil
+	}
+}
+
+func (d *decoder) object() (map[string]any, error) {
+	m := make(map[string]any)
+	for d.dec.More() {
+		start := d.start()
+		tok, err := d.token()
+		if err != nil {
+			return nil, err
+		}
+
+		key := tok.(string)
+		if _, ok := m[key]; ok {
+			return nil, d.errorf(start, "duplicate key %q", key)
+		}
+
+		m[key], err = d.value()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// closing '}'
+	if _, err := d.token(); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func (d *decoder) array() ([]any, error) {
+	s := make([]any, 0)
+	for d.dec.More() {
+		start := d.start()
+		v, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+
+		// unlike a member, an element cannot be left unset
+		if v == nil {
+			return nil, d.errorf(start, "null array element")
+		}
+
+		s = append(s, v)
+	}
+
+	// closing ']'
+	if _, err := d.token(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// start returns the offset of the next token, skipping whitespace and separators.
+func (d *decoder) start() int64 {
+	i := d.dec.InputOffset()
+	for i < int64(len(d.data)) && strings.IndexByte(" \t\r\n,:", d.data[i]) >= 0 {
+		i++
+	}
+
+	return i
+}
+
+func (d *decoder) errorf(offset int64, format string, args ...any) error {
+	return d.wrap(offset, fmt.Errorf(format, args...))
+}
+
+func (d *decoder) wrap(offset int64, err error) error {
+	line, col := position(d.data, offset)
+
+	return fmt.Errorf("line %d, column %d: %w", line, col, err)
+}
+
+func position(data []byte, offset int64) (line, col int) {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+
+	before := data[:offset]
+	line = bytes.Count(before, []byte("\n")) + 1
+	col = int(offset) - bytes.LastIndexByte(before, '\n')
+
+	return line, col
+}
+
+// number returns an integer as int64 or uint64 and a fraction as float64. Integers out of
+// their range keep the original text, which marshals back as a number.
+func number(n json.Number) any {
+	if i, err := strconv.ParseInt(string(n), 10, 64); err == nil {
+		return i
+	}
+
+	if u, err := strconv.ParseUint(string(n), 10, 64); err == nil {
+		return u
+	}
+
+	if strings.ContainsAny(string(n), ".eE") {
+		if f, err := strconv.ParseFloat(string(n), 64); err == nil {
+			return f
+		}
+	}
+
+	return n
+}
@@ -0,0 +1,205 @@
+package json_test
+
+import (
+	"os"
+	"testing"
+
+	zfg "github.com/chaindead/zerocfg"
+	"github.com/chaindead/zerocfg/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		awaited map[string]bool
+		found   map[string]string
+		unknown map[string]string
+	}{
+		{
+			name:  "simple key-value",
+			input: `{"str": "name", "int": 1}`,
+			awaited: map[string]bool{
+				"str": true,
+			},
+			found: map[string]string{
+				"str": `name`,
+			},
+			unknown: map[string]string{
+				"int": `1`,
+			},
+		},
+		{
+			name: "nested",
+			input: `{
+  "host": "localhost",
+  "database": {
+    "port": 5432,
+    "credentials": {"username": "admin"}
+  }
+}`,
+			awaited: map[string]bool{
+				"database.credentials.username": true,
+			},
+			found: map[string]string{
+				"database.credentials.username": `admin`,
+			},
+			unknown: map[string]string{
+				"host":          `localhost`,
+				"database.port": `5432`,
+			},
+		},
+		{
+			name:  "array",
+			input: `{"tags": ["tag1", "tag2", "tag3"], "nums": [1, 2.5]}`,
+			awaited: map[string]bool{
+				"tags": true,
+				"nums": true,
+			},
+			found: map[string]string{
+				"tags": `["tag1","tag2","tag3"]`,
+				"nums": `[1,2.5]`,
+			},
+		},
+		{
+			name:  "map",
+			input: `{"tags": {"k1": 1, "k2": 2}}`,
+			awaited: map[string]bool{
+				"tags": true,
+			},
+			found: map[string]string{
+				"tags": `{"k1":1,"k2":2}`,
+			},
+		},
+		{
+			name:  "scalars",
+			input: `{"b": true, "f": 1.5, "big": 12345678901234567890, "huge": 123456789012345678901234567890, "neg": -9223372036854775808}`,
+			awaited: map[string]bool{
+				"b":    true,
+				"f":    true,
+				"big":  true,
+				"huge": true,
+				"neg":  true,
+			},
+			found: map[string]string{
+				"b":    `true`,
+				"f":    `1.5`,
+				"big":  `12345678901234567890`,
+				"huge": `123456789012345678901234567890`,
+				"neg":  `-9223372036854775808`,
+			},
+		},
+		{
+			name:  "big numbers in list",
+			input: `{"ids": [12345678901234567890, 1, 123456789012345678901234567890]}`,
+			awaited: map[string]bool{
+				"ids": true,
+			},
+			found: map[string]string{
+				"ids": `[12345678901234567890,1,123456789012345678901234567890]`,
+			},
+		},
+		{
+			name:  "null",
+			input: `{"a": null, "db": {"host": null, "port": 5432}, "extra": null}`,
+			awaited: map[string]bool{
+				"a":       true,
+				"db.host": true,
+				"db.port": true,
+			},
+			found: map[string]string{
+				"db.port": `5432`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.found == nil {
+				tt.found = map[string]string{}
+			}
+			if tt.unknown == nil {
+				tt.unknown = map[string]string{}
+			}
+
+			name := tempFile(t, tt.input)
+			p := json.New(&name)
+
+			found, unknown, err := p.Provide(tt.awaited, zfg.ToString)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.found, found)
+			assert.Equal(t, tt.unknown, unknown)
+		})
+	}
+}
+
+func TestParse_Error(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{
+			name:  "syntax",
+			input: "{\n  \"a\": 1,\n  \"b\" 2\n}",
+			err:   "line 3, column 7: invalid character '2' after object key",
+		},
+		{
+			name:  "duplicate key",
+			input: "{\n  \"db\": {\n    \"port\": 1,\n    \"port\": 2\n  }\n}",
+			err:   `line 4, column 5: duplicate key "port"`,
+		},
+		{
+			name:  "unexpected end",
+			input: "{\n  \"a\": [1, 2",
+			err:   "line 2, column 13: unexpected end of JSON input",
+		},
+		{
+			name:  "not an object",
+			input: "\n  [1, 2]",
+			err:   "line 2, column 3: top-level value must be an object",
+		},
+		{
+			name:  "null in array",
+			input: "{\n  \"hosts\": [\"a\", null]\n}",
+			err:   "line 2, column 18: null array element",
+		},
+		{
+			name:  "null in nested array",
+			input: `{"a": [[null]]}`,
+			err:   "line 1, column 9: null array element",
+		},
+		{
+			name:  "trailing data",
+			input: `{"a": 1} {"b": 2}`,
+			err:   "line 1, column 10: unexpected data after top-level object",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := tempFile(t, tt.input)
+			p := json.New(&name)
+
+			_, _, err := p.Provide(map[string]bool{}, zfg.ToString)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
+
+func tempFile(t *testing.T, data string) string {
+	f, err := os.CreateTemp("", "tmpfile-")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, f.Close())
+	})
+
+	_, err = f.WriteString(data)
+	require.NoError(t, err)
+
+	return f.Name()
+}
@@ -10,6 +10,8 @@ type node struct {
 	Value       Value
 	setSource   string
 	isSecret    bool
+	// isDecrypted reports whether the value was decrypted, which hides it as a secret until Reset.
+	isDecrypted bool
 	isRequired  bool
 	caller      string
 }
@@ -22,6 +24,11 @@ func (n *node) pathName() string {
 	return n.caller + ":" + n.Name
 }
 
+// secret reports whether the value must be hidden.
+func (n *node) secret() bool {
+	return n.isSecret || n.isDecrypted
+}
+
 func (n *node) source() string {
 	if n.setSource == "" {
 		return noSource
@@ -0,0 +1,115 @@
+package zerocfg
+
+import "reflect"
+
+var (
+	// savers hold for each option a function saving its current value, used by Override.
+	savers = make(map[*node]func() (restore func()))
+	// defaults hold for each option a function restoring its default value, used by Reset.
+	defaults = make(map[*node]func())
+)
+
+// register remembers how to save the value of an option and saves its default.
+func register[T any](n *node, p *T) {
+	savers[n] = func() func() { return save(p) }
+	defaults[n] = save(p)
+}
+
+// save returns a function restoring the current value of p. Slices and maps are copied,
+// as Set may change them in place; maps are restored in place, as Map returns the map itself.
+func save[T any](p *T) (restore func()) {
+	v := reflect.ValueOf(p).Elem()
+
+	switch v.Kind() {
+	case reflect.Slice:
+		if v.IsNil() {
+			return func() { v.Set(reflect.Zero(v.Type())) }
+		}
+
+		saved := cloneSlice(v)
+
+		return func() { v.Set(cloneSlice(saved)) }
+	case reflect.Map:
+		if v.IsNil() {
+			return func() { v.Set(reflect.Zero(v.Type())) }
+		}
+
+		saved := reflect.MakeMapWithSize(v.Type(), v.Len())
+		copyMap(saved, v)
+
+		return func() {
+			if v.IsNil() {
+				v.Set(reflect.MakeMapWithSize(v.Type(), saved.Len()))
+			}
+
+			for _, k := range v.MapKeys() {
+				v.SetMapIndex(k, reflect.Value{})
+			}
+			copyMap(v, saved)
+		}
+	default:
+		saved := *p
+
+		return func() { *p = saved }
+	}
+}
+
+func cloneSlice(v reflect.Value) reflect.Value {
+	c := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+	reflect.Copy(c, v)
+
+	return c
+}
+
+func copyMap(dst, src reflect.Value) {
+	iter := src.MapRange()
+	for iter.Next() {
+		dst.SetMapIndex(iter.Key(), iter.Value())
+	}
+}
+
+// Reset restores the default values of all options and allows Parse to be called again.
+// It is meant for tests (see the zfgtest package) and must not run concurrently with
+// Parse or code reading options.
+func Reset() {
+	for _, n := range c.vs {
+		if restore, ok := defaults[n]; ok {
+			restore()
+		}
+
+		n.setSource = ""
+		n.isDecrypted = false
+	}
+
+	c.locked = false
+}
+
+// Override sets the value of an option or alias as if it was read from source,
+// regardless of the priority of sources that set it before. It returns a function
+// restoring the previous value and source.
+//
+// Override is meant for tests (see the zfgtest package). Overrides of different
+// options may run concurrently.
+func Override(key, value, source string) (restore func(), err error) {
+	if trueKey, ok := c.aliases[key]; ok {
+		key = trueKey
+	}
+
+	n, ok := c.vs[key]
+	if !ok {
+		return nil, ErrNoSuchKey
+	}
+
+	prev, prevSource := savers[n](), n.setSource
+	if err = n.Value.Set(value); err != nil {
+		prev()
+
+		return nil, err
+	}
+	n.setSource = source
+
+	return func() {
+		prev()
+		n.setSource = prevSource
+	}, nil
+}
@@ -0,0 +1,81 @@
+package zerocfg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Reset(t *testing.T) {
+	c = testConfig()
+
+	port := Int("port", 80, "port")
+	hosts := Strs("hosts", []string{"a"}, "hosts")
+	limits := Map("limits", map[string]any{"max": 1}, "limits")
+
+	require.NoError(t, Parse(newMock(map[string]any{
+		"port":   8080,
+		"hosts":  []string{"b", "c"},
+		"limits": map[string]any{"min": 2},
+	})))
+	require.ErrorIs(t, Parse(), ErrDoubleParse)
+
+	Reset()
+
+	require.Equal(t, 80, *port)
+	require.Equal(t, []string{"a"}, *hosts)
+	require.Equal(t, map[string]any{"max": 1}, limits)
+	require.Equal(t, noSource, c.vs["port"].source())
+
+	require.NoError(t, Parse(newMock(map[string]any{"port": 9090})))
+	require.Equal(t, 9090, *port)
+}
+
+func Test_ResetTyped(t *testing.T) {
+	c = testConfig()
+
+	ip := IP("ip", "", "ip")
+	ports := Ints("ports", []int{1, 2}, "ports")
+
+	require.NoError(t, Parse(newMock(map[string]any{
+		"ip":    "10.0.0.1",
+		"ports": []int{3, 4},
+	})))
+	require.Equal(t, "10.0.0.1", ip.String())
+
+	Reset()
+
+	require.Nil(t, *ip)
+	require.Equal(t, []int{1, 2}, *ports)
+}
+
+func Test_Override(t *testing.T) {
+	c = testConfig()
+
+	port := Int("port", 80, "port", Alias("p"))
+	ip := IP("ip", "", "ip")
+	require.NoError(t, Parse(newMock(map[string]any{"port": 8080})))
+
+	restore, err := Override("p", "9090", "test")
+	require.NoError(t, err)
+	require.Equal(t, 9090, *port)
+	require.Equal(t, "test", c.vs["port"].source())
+
+	restore()
+	require.Equal(t, 8080, *port)
+	require.Equal(t, mockType, c.vs["port"].source())
+
+	_, err = Override("missing", "1", "test")
+	require.ErrorIs(t, err, ErrNoSuchKey)
+
+	_, err = Override("port", "not a number", "test")
+	require.Error(t, err)
+	require.Equal(t, 8080, *port)
+
+	restore, err = Override("ip", "10.0.0.1", "test")
+	require.NoError(t, err)
+	require.Equal(t, "10.0.0.1", ip.String())
+
+	restore()
+	require.Nil(t, *ip)
+}
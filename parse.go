@@ -1,8 +1,11 @@
 package zerocfg
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 )
 
 // Provider defines a configuration source for zerocfg.
@@ -23,14 +26,174 @@ type Provider interface {
 	Provide(awaited map[string]bool, conv func(any) string) (found, unknown map[string]string, err error)
 }
 
+// Sourcer is an optional interface for providers which merge several underlying sources,
+// such as multiple files. Source reports where the value of a found key came from;
+// Parse records it as the option source instead of Type(). An empty result falls back to Type().
+type Sourcer interface {
+	Source(key string) string
+}
+
+// Locator is an optional interface for providers which know where keys are defined,
+// such as file providers. Locate returns a position like "app.yaml:3:5", or "" if unknown.
+// Parse attaches positions to unknown keys (see UnknownPositionError) and to errors of setting values.
+type Locator interface {
+	Locate(key string) string
+}
+
+// OptionInfo describes a registered option for providers implementing Inspector.
+type OptionInfo struct {
+	// Type is the option type name, as returned by Value.Type().
+	Type string
+	// Secret reports whether the option is marked with Secret().
+	Secret bool
+}
+
+// Inspector is an optional interface for providers which need more than option names,
+// e.g. to validate values against option types. Parse calls Inspect before Provide
+// with all option names and aliases.
+type Inspector interface {
+	Inspect(options map[string]OptionInfo)
+}
+
+// ContextProvider is an optional interface for providers doing I/O, such as network requests.
+// ParseContext calls ProvideContext instead of Provide with a context carrying the cancellation
+// and the deadline of the provider (see ProviderTimeout).
+type ContextProvider interface {
+	ProvideContext(ctx context.Context, awaited map[string]bool, conv func(any) string) (found, unknown map[string]string, err error)
+}
+
+// ParseOption configures ParseWith and ParseContext.
+type ParseOption func(*parseOptions)
+
+type parseOptions struct {
+	providers []Provider
+	// group is the Concurrent group number of each provider, 0 for providers fetched alone.
+	group []int
+	// groups is the number of Concurrent groups.
+	groups int
+	// flagsAt is the position of the built-in flag provider, or -1 to disable it.
+	flagsAt int
+	// timeout limits each provider, zero means no limit.
+	timeout time.Duration
+	// concurrency is the number of providers of a group fetched at a time, zero means all.
+	concurrency int
+	// decrypter decrypts found values, if set.
+	decrypter Decrypter
+}
+
+// Providers returns a ParseOption that adds providers in priority order (first = higher priority).
+func Providers(ps ...Provider) ParseOption {
+	return func(o *parseOptions) {
+		o.providers = append(o.providers, ps...)
+		o.group = append(o.group, make([]int, len(ps))...)
+	}
+}
+
+// Concurrent returns a ParseOption that adds independent providers in priority order,
+// which are fetched at the same time, e.g. several remote sources. Results are still
+// applied in priority order, and errors are reported for the provider with the highest priority.
+//
+// Providers before the group are applied before it is fetched, so the group may read options
+// set by them (e.g. a vault token from flags or env). Providers of a group must not read
+// options set by each other: they see the values from before the group.
+//
+// Usage:
+//
+//	zerocfg.ParseWith(zerocfg.Providers(env.New()), zerocfg.Concurrent(vault.New(secrets), consul.New("app")))
+func Concurrent(ps ...Provider) ParseOption {
+	return func(o *parseOptions) {
+		o.groups++
+		o.providers = append(o.providers, ps...)
+		for range ps {
+			o.group = append(o.group, o.groups)
+		}
+	}
+}
+
+// NoFlags returns a ParseOption that disables the built-in flag provider reading os.Args,
+// e.g. in libraries and test binaries. A flag provider with explicit arguments can be
+// passed as a regular provider: flag.New(flag.WithArgs(args)).
+func NoFlags() ParseOption {
+	return func(o *parseOptions) {
+		o.flagsAt = -1
+	}
+}
+
+// FlagsAt returns a ParseOption that places the built-in flag provider at position i
+// of the providers: 0 is the highest priority (default), 1 goes after the first provider, and so on.
+//
+// Usage:
+//
+//	// env overrides flags
+//	zerocfg.ParseWith(zerocfg.Providers(env.New(), yaml.New(path)), zerocfg.FlagsAt(1))
+func FlagsAt(i int) ParseOption {
+	return func(o *parseOptions) {
+		o.flagsAt = i
+	}
+}
+
+// ProviderTimeout returns a ParseOption that limits the time of each provider.
+// Providers implementing ContextProvider get the deadline in their context. Other providers
+// are abandoned when it expires: Parse returns the error, while Provide keeps running in the background.
+//
+// Usage:
+//
+//	zerocfg.ParseContext(ctx, zerocfg.Providers(http.New(url)), zerocfg.ProviderTimeout(5*time.Second))
+func ProviderTimeout(d time.Duration) ParseOption {
+	return func(o *parseOptions) {
+		o.timeout = d
+	}
+}
+
+// Concurrency returns a ParseOption that fetches up to n providers of a Concurrent group
+// at a time. By default all providers of a group are fetched at once.
+//
+// Usage:
+//
+//	zerocfg.ParseWith(zerocfg.Concurrent(vault.New(secrets), consul.New("app"), http.New(url)), zerocfg.Concurrency(2))
+func Concurrency(n int) ParseOption {
+	return func(o *parseOptions) {
+		o.concurrency = n
+	}
+}
+
+// stages returns the providers in priority order with the built-in ones inserted,
+// split into stages fetched one after another: a Concurrent group or a single provider.
+func (o *parseOptions) stages(builtin []Provider) [][]Provider {
+	ps, group := o.providers, o.group
+	if o.flagsAt >= 0 {
+		i := o.flagsAt
+		if i > len(ps) {
+			i = len(ps)
+		}
+
+		ps = append(append(append([]Provider{}, ps[:i]...), builtin...), ps[i:]...)
+		group = append(append(append([]int{}, group[:i]...), make([]int, len(builtin))...), group[i:]...)
+	}
+
+	var stages [][]Provider
+	for i, p := range ps {
+		if i > 0 && group[i] != 0 && group[i] == group[i-1] {
+			stages[len(stages)-1] = append(stages[len(stages)-1], p)
+
+			continue
+		}
+
+		stages = append(stages, []Provider{p})
+	}
+
+	return stages
+}
+
 // Parse loads configuration from the provided sources in priority order.
+// It is a shorthand for ParseWith(Providers(ps...)).
 //
 // Usage:
 //
 //	err := zerocfg.Parse(env.New(), yaml.New(path))
 //
 // Priority:
-//  1. Command-line flags (always highest)
+//  1. Command-line flags (highest, see FlagsAt and NoFlags)
 //  2. Parsers in the order provided (first = higher priority)
 //  3. Default values (lowest)
 //
@@ -41,28 +204,71 @@ type Provider interface {
 // Error Handling:
 //   - UnknownFieldError: for unknown keys (see IsUnknown)
 //   - ErrRequired: for missing required options
-//   - ErrDoubleParse: if called multiple times
+//   - ErrDoubleParse: if called multiple times (see Reset)
 func Parse(ps ...Provider) error {
+	return ParseWith(Providers(ps...))
+}
+
+// ParseWith loads configuration as configured by the options, see Parse.
+//
+// Usage:
+//
+//	err := zerocfg.ParseWith(zerocfg.Providers(env.New(), yaml.New(path)), zerocfg.NoFlags())
+func ParseWith(opts ...ParseOption) error {
+	return ParseContext(context.Background(), opts...)
+}
+
+// ParseContext is ParseWith with a context, which is passed to providers implementing ContextProvider.
+// When ctx is done or a provider exceeds ProviderTimeout, the error names the provider and
+// matches the context error (errors.Is(err, context.DeadlineExceeded)).
+//
+// Usage:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+//	defer cancel()
+//	err := zerocfg.ParseContext(ctx, zerocfg.Providers(vault.New(secrets)), zerocfg.ProviderTimeout(10*time.Second))
+func ParseContext(ctx context.Context, opts ...ParseOption) error {
+	o := parseOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
 	if c.locked {
 		return ErrDoubleParse
 	}
 	c.locked = true
-	c.parsers = append(c.parsers, ps...)
 	awaited := c.awaited()
 
 	uErr := make(UnknownFieldError)
-	for _, p := range c.parsers {
-		found, unknown, err := p.Provide(awaited, ToString)
-		if err != nil {
-			return fmt.Errorf("parse %q: %w", p.Type(), err)
-		}
+	positions := make(map[string]map[string]string)
+	for _, ps := range o.stages(c.parsers) {
+		results := o.fetch(ctx, ps, awaited)
+		for i, p := range ps {
+			r := results[i]
+			if r.err != nil {
+				return fmt.Errorf("parse %q: %w", p.Type(), r.err)
+			}
 
-		err = c.applyParser(p.Type(), found)
-		if err != nil {
-			return fmt.Errorf("apply %q: %w", p.Type(), err)
+			if o.decrypter != nil {
+				if err := c.decrypt(o.decrypter, r.found); err != nil {
+					return fmt.Errorf("decrypt %q: %w", p.Type(), err)
+				}
+			}
+
+			err := c.applyProvider(p, r.found)
+			if err != nil {
+				return fmt.Errorf("apply %q: %w", p.Type(), err)
+			}
+
+			uErr.add(p.Type(), r.unknown)
+			if l, ok := p.(Locator); ok {
+				addPositions(positions, p.Type(), r.unknown, l)
+			}
 		}
+	}
 
-		uErr.add(p.Type(), unknown)
+	if len(positions) != 0 {
+		return &UnknownPositionError{UnknownFieldError: uErr, Positions: positions}
 	}
 
 	if len(uErr) != 0 {
@@ -82,6 +288,157 @@ func Parse(ps ...Provider) error {
 	return nil
 }
 
+// fetched is the result of a provider.
+type fetched struct {
+	found, unknown map[string]string
+	err            error
+}
+
+// fetch returns the results of a stage in priority order, up to the first error.
+// Providers of a group are started in priority order, with at most o.concurrency running at a time.
+// All results are awaited before any is applied, so providers never read options while they are set.
+func (o *parseOptions) fetch(ctx context.Context, ps []Provider, awaited map[string]bool) []fetched {
+	if len(ps) == 1 {
+		var r fetched
+		if in, ok := ps[0].(Inspector); ok {
+			in.Inspect(c.options())
+		}
+
+		r.found, r.unknown, r.err = o.provide(ctx, ps[0], awaited)
+
+		return []fetched{r}
+	}
+
+	limit := o.concurrency
+	if limit <= 0 || limit > len(ps) {
+		limit = len(ps)
+	}
+
+	// stop fetching the group after the first error
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]chan fetched, len(ps))
+	for i, p := range ps {
+		results[i] = make(chan fetched, 1)
+		if in, ok := p.(Inspector); ok {
+			in.Inspect(c.options())
+		}
+	}
+
+	go func() {
+		sem := make(chan struct{}, limit)
+		for i, p := range ps {
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				results[i] <- fetched{err: ctx.Err()}
+
+				continue
+			}
+
+			go func(i int, p Provider) {
+				defer func() { <-sem }()
+
+				var r fetched
+				r.found, r.unknown, r.err = o.provide(ctx, p, awaited)
+				results[i] <- r
+			}(i, p)
+		}
+	}()
+
+	rs := make([]fetched, 0, len(ps))
+	for _, r := range results {
+		rs = append(rs, <-r)
+		if rs[len(rs)-1].err != nil {
+			break
+		}
+	}
+
+	return rs
+}
+
+// provide runs p within the provider timeout.
+func (o *parseOptions) provide(ctx context.Context, p Provider, awaited map[string]bool) (found, unknown map[string]string, err error) {
+	if o.timeout <= 0 {
+		return provide(ctx, p, awaited, ToString)
+	}
+
+	pctx, cancel := context.WithTimeout(ctx, o.timeout)
+	defer cancel()
+
+	found, unknown, err = provide(pctx, p, awaited, ToString)
+	if err != nil && ctx.Err() == nil && errors.Is(pctx.Err(), context.DeadlineExceeded) {
+		return nil, nil, fmt.Errorf("timed out after %s: %w", o.timeout, err)
+	}
+
+	return found, unknown, err
+}
+
+// provide calls ProvideContext of p, or Provide of providers without context support.
+// The latter run in a separate goroutine and are abandoned when ctx is done,
+// unless ctx can never be done.
+func provide(ctx context.Context, p Provider, awaited map[string]bool, conv func(any) string) (found, unknown map[string]string, err error) {
+	if err = ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	if cp, ok := p.(ContextProvider); ok {
+		return cp.ProvideContext(ctx, awaited, conv)
+	}
+
+	if ctx.Done() == nil {
+		return p.Provide(awaited, conv)
+	}
+
+	type result struct {
+		found, unknown map[string]string
+		err            error
+	}
+
+	done := make(chan result, 1)
+	go func() {
+		var r result
+		r.found, r.unknown, r.err = p.Provide(awaited, conv)
+		done <- r
+	}()
+
+	select {
+	case r := <-done:
+		return r.found, r.unknown, r.err
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+}
+
+func (c *config) applyProvider(p Provider, vs map[string]string) error {
+	s, isSourcer := p.(Sourcer)
+	l, isLocator := p.(Locator)
+	if !isSourcer && !isLocator {
+		return c.applyParser(p.Type(), vs)
+	}
+
+	for k, v := range vs {
+		source := p.Type()
+		if isSourcer && s.Source(k) != "" {
+			source = s.Source(k)
+		}
+
+		err := c.set(source, k, v)
+		if err == nil {
+			continue
+		}
+
+		if isLocator && l.Locate(k) != "" {
+			return fmt.Errorf("set key=%q at %s: %w", k, l.Locate(k), err)
+		}
+
+		return fmt.Errorf("set key=%q: %w", k, err)
+	}
+
+	return nil
+}
+
 func (c *config) applyParser(source string, vs map[string]string) error {
 	for k, v := range vs {
 		err := c.set(source, k, v)
@@ -0,0 +1,140 @@
+package properties
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// decode parses Java .properties data into a map of keys to values.
+// Keys are a flat namespace, so "a" and "a.b" may both be set.
+//
+// The format follows java.util.Properties:
+//   - comment lines starting with '#' or '!'
+//   - key and value separated by '=', ':' or whitespace
+//   - logical lines continued by an odd number of trailing backslashes
+//   - escape sequences \t, \n, \r, \f and \uXXXX; any other escaped character stands for itself
+//   - later duplicates override earlier ones
+//
+// Unlike Java, the input is read as UTF-8.
+func decode(data []byte) (map[string]string, error) {
+	settings := make(map[string]string)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for n := 1; scanner.Scan(); n++ {
+		start := n
+		line := strings.TrimLeft(scanner.Text(), " \t\f")
+
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+
+		for continued(line) && scanner.Scan() {
+			n++
+			line = line[:len(line)-1] + strings.TrimLeft(scanner.Text(), " \t\f")
+		}
+		if continued(line) {
+			line = line[:len(line)-1]
+		}
+
+		rawKey, rawValue := split(line)
+
+		key, err := unescape(rawKey)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", start, err)
+		}
+
+		value, err := unescape(rawValue)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", start, err)
+		}
+
+		settings[key] = value
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// continued reports whether the line ends with an odd number of backslashes.
+func continued(line string) bool {
+	n := len(line) - len(strings.TrimRight(line, `\`))
+
+	return n%2 == 1
+}
+
+// split separates the key from the value of a logical line.
+func split(line string) (key, value string) {
+	i := 0
+	for ; i < len(line); i++ {
+		ch := line[i]
+		if ch == '\\' {
+			i++
+			continue
+		}
+
+		if ch == '=' || ch == ':' || ch == ' ' || ch == '\t' || ch == '\f' {
+			break
+		}
+	}
+
+	if i >= len(line) {
+		return line, ""
+	}
+
+	key = line[:i]
+	rest := strings.TrimLeft(line[i:], " \t\f")
+	if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+		rest = strings.TrimLeft(rest[1:], " \t\f")
+	}
+
+	return key, rest
+}
+
+func unescape(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		if ch != '\\' || i+1 == len(s) {
+			b.WriteByte(ch)
+			continue
+		}
+
+		i++
+		switch s[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			if i+5 > len(s) {
+				return "", fmt.Errorf("invalid unicode escape %q", s[i-1:])
+			}
+
+			code, err := strconv.ParseUint(s[i+1:i+5], 16, 16)
+			if err != nil {
+				return "", fmt.Errorf("invalid unicode escape %q", s[i-1:i+5])
+			}
+
+			b.WriteRune(rune(code))
+			i += 4
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+
+	return b.String(), nil
+}
@@ -0,0 +1,113 @@
+package properties
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+
+	"github.com/chaindead/zerocfg/util"
+)
+
+type Provider struct {
+	src util.Source
+
+	conv    func(any) string
+	awaited map[string]bool
+}
+
+func New(path *string) *Provider {
+	return &Provider{src: util.FileSource(path)}
+}
+
+// FromBytes creates a Provider reading the given data.
+func FromBytes(data []byte) *Provider {
+	return &Provider{src: util.BytesSource(data)}
+}
+
+// FromReader creates a Provider reading r on the first Provide; its content is reused afterwards.
+func FromReader(r io.Reader) *Provider {
+	return &Provider{src: util.ReaderSource(r)}
+}
+
+// FromFS creates a Provider reading the named file of fsys, e.g. an embed.FS.
+func FromFS(fsys fs.FS, name string) *Provider {
+	return &Provider{src: util.FSSource(fsys, name)}
+}
+
+func (p *Provider) Type() string {
+	return fmt.Sprintf("properties[%s]", p.src.Name())
+}
+
+func (p *Provider) Provide(keys map[string]bool, conv func(any) string) (found, unknown map[string]string, err error) {
+	data, err := p.src.Load()
+	if err != nil {
+		return nil, nil, fmt.Errorf("read properties file: %w", err)
+	}
+
+	p.conv = conv
+	p.awaited = keys
+
+	return p.parse(data)
+}
+
+func (p *Provider) parse(data []byte) (found, unknown map[string]string, err error) {
+	settings, err := decode(data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unmarshal properties: %w", err)
+	}
+
+	found, unknown = p.flatten(settings)
+
+	return found, unknown, nil
+}
+
+// flatten matches keys against the awaited options. A key under an awaited option,
+// which is not set itself, is a value of that map option.
+func (p *Provider) flatten(settings map[string]string) (found, unknown map[string]string) {
+	found, unknown = make(map[string]string), make(map[string]string)
+	maps := make(map[string]map[string]any)
+
+	for k, v := range settings {
+		if p.awaited[k] {
+			found[k] = v
+
+			continue
+		}
+
+		if prefix, ok := p.mapPrefix(settings, k); ok {
+			if maps[prefix] == nil {
+				maps[prefix] = make(map[string]any)
+			}
+			maps[prefix][k[len(prefix)+1:]] = v
+
+			continue
+		}
+
+		unknown[k] = v
+	}
+
+	for prefix, m := range maps {
+		found[prefix] = p.conv(m)
+	}
+
+	return found, unknown
+}
+
+// mapPrefix returns the longest awaited prefix of key, unless the prefix is set itself.
+func (p *Provider) mapPrefix(settings map[string]string, key string) (string, bool) {
+	for i := strings.LastIndexByte(key, '.'); i > 0; i = strings.LastIndexByte(key[:i], '.') {
+		prefix := key[:i]
+		if !p.awaited[prefix] {
+			continue
+		}
+
+		if _, isSet := settings[prefix]; isSet {
+			return "", false
+		}
+
+		return prefix, true
+	}
+
+	return "", false
+}
@@ -0,0 +1,175 @@
+package properties_test
+
+import (
+	"os"
+	"testing"
+
+	zfg "github.com/chaindead/zerocfg"
+	"github.com/chaindead/zerocfg/properties"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		awaited map[string]bool
+		found   map[string]string
+		unknown map[string]string
+	}{
+		{
+			name: "separators",
+			input: `
+# comment
+! comment
+eq = 1
+colon: 2
+space 3
+  spaced   =   4`,
+			awaited: map[string]bool{
+				"eq":     true,
+				"colon":  true,
+				"space":  true,
+				"spaced": true,
+			},
+			found: map[string]string{
+				"eq":     `1`,
+				"colon":  `2`,
+				"space":  `3`,
+				"spaced": `4`,
+			},
+		},
+		{
+			name: "dotted keys",
+			input: `
+host=localhost
+database.port=5432
+database.credentials.username=admin`,
+			awaited: map[string]bool{
+				"database.credentials.username": true,
+			},
+			found: map[string]string{
+				"database.credentials.username": `admin`,
+			},
+			unknown: map[string]string{
+				"host":          `localhost`,
+				"database.port": `5432`,
+			},
+		},
+		{
+			name: "map",
+			input: `
+limits.max=10
+limits.min=1`,
+			awaited: map[string]bool{
+				"limits": true,
+			},
+			found: map[string]string{
+				"limits": `{"max":"10","min":"1"}`,
+			},
+		},
+		{
+			name: "flat keys",
+			input: `
+log4j.logger.app=DEBUG
+log4j.logger=INFO
+log4j.logger.app.db=WARN`,
+			awaited: map[string]bool{
+				"log4j.logger":     true,
+				"log4j.logger.app": true,
+			},
+			found: map[string]string{
+				"log4j.logger":     `INFO`,
+				"log4j.logger.app": `DEBUG`,
+			},
+			unknown: map[string]string{
+				"log4j.logger.app.db": `WARN`,
+			},
+		},
+		{
+			name: "escapes and continuation",
+			input: `
+escaped\ key\=x = a\tb\u00e9\:c
+continued = first, \
+            second, \
+            third
+backslash = C:\\path
+empty
+override = 1
+override = 2`,
+			awaited: map[string]bool{
+				"escaped key=x": true,
+				"continued":     true,
+				"backslash":     true,
+				"empty":         true,
+				"override":      true,
+			},
+			found: map[string]string{
+				"escaped key=x": "a\tbé:c",
+				"continued":     `first, second, third`,
+				"backslash":     `C:\path`,
+				"empty":         ``,
+				"override":      `2`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.found == nil {
+				tt.found = map[string]string{}
+			}
+			if tt.unknown == nil {
+				tt.unknown = map[string]string{}
+			}
+
+			name := tempFile(t, tt.input)
+			p := properties.New(&name)
+
+			found, unknown, err := p.Provide(tt.awaited, zfg.ToString)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.found, found)
+			assert.Equal(t, tt.unknown, unknown)
+		})
+	}
+}
+
+func TestParse_Error(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{
+			name:  "invalid unicode escape",
+			input: "\na = \\u00zz",
+			err:   `line 2: invalid unicode escape "\\u00zz"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := tempFile(t, tt.input)
+			p := properties.New(&name)
+
+			_, _, err := p.Provide(map[string]bool{}, zfg.ToString)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
+
+func tempFile(t *testing.T, data string) string {
+	f, err := os.CreateTemp("", "tmpfile-")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, f.Close())
+	})
+
+	_, err = f.WriteString(data)
+	require.NoError(t, err)
+
+	return f.Name()
+}
@@ -78,8 +78,8 @@ func yamlDescription(n *node) string {
 }
 
 func yamlValue(n *node) string {
-	if n.isSecret {
-		return "<secret>"
+	if n.secret() {
+		return secretValue
 	}
 
 	return ToString(n.Value)
@@ -0,0 +1,114 @@
+package zerocfg
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const secretValue = "<secret>"
+
+// SnapshotEntry describes a single resolved configuration option.
+type SnapshotEntry struct {
+	// Key is the full dotted option name.
+	Key string `json:"-"`
+	// Value is the string representation of the option value, or "<secret>" for secret options.
+	Value string `json:"value"`
+	// Type is the option type name as reported by Value.Type().
+	Type string `json:"type"`
+	// Source is the provider that set the value, or "default".
+	Source string `json:"source"`
+	// IsSecret reports whether the option is marked with Secret() or has a decrypted value.
+	IsSecret bool `json:"secret"`
+	// IsDefault reports whether the option still holds its default value.
+	IsDefault bool `json:"default"`
+}
+
+// ConfigSnapshot is an immutable view of the resolved configuration returned by Snapshot.
+type ConfigSnapshot struct {
+	entries []SnapshotEntry
+}
+
+// Snapshot returns the resolved configuration as data.
+//
+// Secret values are masked the same way as in Show. The snapshot does not change
+// when options are updated afterwards.
+//
+// Usage:
+//
+//	data, err := json.Marshal(zfg.Snapshot())
+//	// {"db":{"port":{"value":"5678","type":"uint","source":"default","secret":false,"default":true}}}
+func Snapshot() ConfigSnapshot {
+	entries := make([]SnapshotEntry, 0, len(c.vs))
+	for _, n := range c.vs {
+		entries = append(entries, SnapshotEntry{
+			Key:       n.Name,
+			Value:     yamlValue(n),
+			Type:      n.Value.Type(),
+			Source:    n.source(),
+			IsSecret:  n.secret(),
+			IsDefault: n.setSource == "",
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Key < entries[j].Key
+	})
+
+	return ConfigSnapshot{entries: entries}
+}
+
+// Entries returns all options of the snapshot sorted by key.
+func (s ConfigSnapshot) Entries() []SnapshotEntry {
+	entries := make([]SnapshotEntry, len(s.entries))
+	copy(entries, s.entries)
+
+	return entries
+}
+
+// Get returns the option with the given key.
+func (s ConfigSnapshot) Get(key string) (SnapshotEntry, bool) {
+	i := sort.Search(len(s.entries), func(i int) bool {
+		return s.entries[i].Key >= key
+	})
+	if i < len(s.entries) && s.entries[i].Key == key {
+		return s.entries[i], true
+	}
+
+	return SnapshotEntry{}, false
+}
+
+// MarshalJSON encodes the snapshot as an object nested by the dotted option keys.
+// It returns an error if an option key is a prefix of another one, e.g. "db" and "db.port",
+// as the option cannot be both a value and an object.
+func (s ConfigSnapshot) MarshalJSON() ([]byte, error) {
+	root := make(map[string]any)
+	for _, e := range s.entries {
+		parts := strings.Split(e.Key, ".")
+
+		cur := root
+		for _, part := range parts[:len(parts)-1] {
+			if prev, ok := cur[part].(SnapshotEntry); ok {
+				return nil, fmt.Errorf("snapshot: option %q conflicts with option %q", e.Key, prev.Key)
+			}
+
+			next, ok := cur[part].(map[string]any)
+			if !ok {
+				next = make(map[string]any)
+				cur[part] = next
+			}
+
+			cur = next
+		}
+
+		last := parts[len(parts)-1]
+		if _, ok := cur[last].(map[string]any); ok {
+			return nil, fmt.Errorf("snapshot: option %q conflicts with options under it", e.Key)
+		}
+
+		cur[last] = e
+	}
+
+	return json.Marshal(root)
+}
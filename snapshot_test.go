@@ -0,0 +1,74 @@
+package zerocfg
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Snapshot(t *testing.T) {
+	c = testConfig()
+
+	Uint("db.port", 5678, "database port")
+	Str("db.password", "qwerty", "password", Secret())
+	Str("name", "", "name")
+
+	err := Parse(newMock(map[string]any{
+		"name":        "app",
+		"db.password": "pass",
+	}))
+	require.NoError(t, err)
+
+	s := Snapshot()
+	require.Equal(t, []SnapshotEntry{
+		{Key: "db.password", Value: "<secret>", Type: "string", Source: mockType, IsSecret: true},
+		{Key: "db.port", Value: "5678", Type: "uint", Source: "default", IsDefault: true},
+		{Key: "name", Value: "app", Type: "string", Source: mockType},
+	}, s.Entries())
+
+	e, ok := s.Get("db.port")
+	require.True(t, ok)
+	require.Equal(t, "5678", e.Value)
+
+	_, ok = s.Get("db")
+	require.False(t, ok)
+
+	data, err := json.Marshal(s)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"db": {
+			"password": {"value": "<secret>", "type": "string", "source": "mock", "secret": true, "default": false},
+			"port": {"value": "5678", "type": "uint", "source": "default", "secret": false, "default": true}
+		},
+		"name": {"value": "app", "type": "string", "source": "mock", "secret": false, "default": false}
+	}`, string(data))
+}
+
+func Test_SnapshotImmutable(t *testing.T) {
+	c = testConfig()
+
+	v := Str("name", "before", "name")
+	require.NoError(t, Parse())
+
+	s := Snapshot()
+	*v = "after"
+	s.Entries()[0].Value = "changed"
+
+	e, ok := s.Get("name")
+	require.True(t, ok)
+	require.Equal(t, "before", e.Value)
+}
+
+func Test_SnapshotConflict(t *testing.T) {
+	c = testConfig()
+
+	Str("db", "", "")
+	Uint("db.port", 5678, "")
+	require.NoError(t, Parse())
+
+	require.Len(t, Snapshot().Entries(), 2)
+
+	_, err := json.Marshal(Snapshot())
+	require.ErrorContains(t, err, `snapshot: option "db.port" conflicts with option "db"`)
+}
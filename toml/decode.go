@@ -0,0 +1,63 @@
+package toml
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/BurntSushi/toml"
+)
+
+func decode(data []byte) (map[string]any, error) {
+	settings := make(map[string]any)
+	if _, err := toml.Decode(string(data), &settings); err != nil {
+		var parseErr toml.ParseError
+		if errors.As(err, &parseErr) {
+			return nil, fmt.Errorf("line %d: %s", parseErr.Position.Line, parseErr.Message)
+		}
+
+		return nil, err
+	}
+
+	return plain(settings).(map[string]any), nil
+}
+
+// plain converts date-times into strings accepted by the zerocfg Time option,
+// keeping the precision of local dates and times.
+func plain(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			v[k] = plain(e)
+		}
+
+		return v
+	case []map[string]any:
+		s := make([]any, 0, len(v))
+		for _, e := range v {
+			s = append(s, plain(e))
+		}
+
+		return s
+	case []any:
+		for i, e := range v {
+			v[i] = plain(e)
+		}
+
+		return v
+	case time.Time:
+		// local date-times are decoded in zones named by their kind
+		switch v.Location().String() {
+		case "datetime-local":
+			return v.Format("2006-01-02T15:04:05.999999999")
+		case "date-local":
+			return v.Format("2006-01-02")
+		case "time-local":
+			return v.Format("15:04:05.999999999")
+		default:
+			return v.Format(time.RFC3339Nano)
+		}
+	default:
+		return v
+	}
+}
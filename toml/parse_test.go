@@ -0,0 +1,378 @@
+package toml_test
+
+import (
+	"os"
+	"testing"
+
+	zfg "github.com/chaindead/zerocfg"
+	"github.com/chaindead/zerocfg/toml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		awaited map[string]bool
+		found   map[string]string
+		unknown map[string]string
+	}{
+		{
+			name: "simple key-value",
+			input: `
+str = "name" # comment
+int = 1`,
+			awaited: map[string]bool{
+				"str": true,
+			},
+			found: map[string]string{
+				"str": `name`,
+			},
+			unknown: map[string]string{
+				"int": `1`,
+			},
+		},
+		{
+			name: "tables and dotted keys",
+			input: `
+host = "localhost"
+
+[database]
+port = 5432
+credentials.username = "admin"
+
+[database.pool]
+size = 10`,
+			awaited: map[string]bool{
+				"database.credentials.username": true,
+				"database.pool.size":            true,
+			},
+			found: map[string]string{
+				"database.credentials.username": `admin`,
+				"database.pool.size":            `10`,
+			},
+			unknown: map[string]string{
+				"host":          `localhost`,
+				"database.port": `5432`,
+			},
+		},
+		{
+			name: "quoted keys",
+			input: `
+"quoted.key" = 1
+site."google.com" = true
+'literal' = 2`,
+			awaited: map[string]bool{
+				"quoted.key":      true,
+				"site.google.com": true,
+			},
+			found: map[string]string{
+				"quoted.key":      `1`,
+				"site.google.com": `true`,
+			},
+			unknown: map[string]string{
+				"literal": `2`,
+			},
+		},
+		{
+			name: "arrays",
+			input: `
+tags = ["tag1", 'tag2', """tag3"""]
+nums = [
+  1,
+  2, # comment
+  3,
+]
+nested = [[1, 2], ["a"]]`,
+			awaited: map[string]bool{
+				"tags":   true,
+				"nums":   true,
+				"nested": true,
+			},
+			found: map[string]string{
+				"tags":   `["tag1","tag2","tag3"]`,
+				"nums":   `[1,2,3]`,
+				"nested": `[[1,2],["a"]]`,
+			},
+		},
+		{
+			name: "inline table",
+			input: `
+limits = { max = 10, min = 1 }
+point = { x.y = 1 }`,
+			awaited: map[string]bool{
+				"limits":    true,
+				"point.x.y": true,
+			},
+			found: map[string]string{
+				"limits":    `{"max":10,"min":1}`,
+				"point.x.y": `1`,
+			},
+		},
+		{
+			name: "array of tables",
+			input: `
+[[servers]]
+name = "a"
+
+[servers.tls]
+enabled = true
+
+[[servers]]
+name = "b"`,
+			awaited: map[string]bool{
+				"servers": true,
+			},
+			found: map[string]string{
+				"servers": `[{"name":"a","tls":{"enabled":true}},{"name":"b"}]`,
+			},
+		},
+		{
+			name: "strings",
+			input: `
+basic = "tab\there \"quoted\" \u00e9"
+literal = 'C:\Users\path'
+multi = """
+first
+second"""
+trimmed = """\
+    one \
+    two"""
+quotes = """a""b"""""
+raw = '''
+line\n'''`,
+			awaited: map[string]bool{
+				"basic":   true,
+				"literal": true,
+				"multi":   true,
+				"trimmed": true,
+				"quotes":  true,
+				"raw":     true,
+			},
+			found: map[string]string{
+				"basic":   "tab\there \"quoted\" é",
+				"literal": `C:\Users\path`,
+				"multi":   "first\nsecond",
+				"trimmed": "one two",
+				"quotes":  `a""b""`,
+				"raw":     `line\n`,
+			},
+		},
+		{
+			name: "numbers and booleans",
+			input: `
+dec = +1_000
+hex = 0xDEAD_beef
+oct = 0o755
+bin = 0b1101
+neg = -17
+flt = 6.626e-34
+exp = 5e+22
+under = 224_617.445_991
+inf = -inf
+yes = true
+no = false`,
+			awaited: map[string]bool{
+				"dec":   true,
+				"hex":   true,
+				"oct":   true,
+				"bin":   true,
+				"neg":   true,
+				"flt":   true,
+				"exp":   true,
+				"under": true,
+				"inf":   true,
+				"yes":   true,
+				"no":    true,
+			},
+			found: map[string]string{
+				"dec":   `1000`,
+				"hex":   `3735928559`,
+				"oct":   `493`,
+				"bin":   `13`,
+				"neg":   `-17`,
+				"flt":   `6.626e-34`,
+				"exp":   `5e+22`,
+				"under": `224617.445991`,
+				"inf":   `-Inf`,
+				"yes":   `true`,
+				"no":    `false`,
+			},
+		},
+		{
+			name: "datetimes",
+			input: `
+odt = 1979-05-27T07:32:00Z
+offset = 1979-05-27 00:32:00.999999-07:00
+ldt = 1979-05-27T07:32:00
+ld = 1979-05-27
+lt = 07:32:00.5
+list = [1979-05-27, 1980-01-01]`,
+			awaited: map[string]bool{
+				"odt":    true,
+				"offset": true,
+				"ldt":    true,
+				"ld":     true,
+				"lt":     true,
+				"list":   true,
+			},
+			found: map[string]string{
+				"odt":    `1979-05-27T07:32:00Z`,
+				"offset": `1979-05-27T00:32:00.999999-07:00`,
+				"ldt":    `1979-05-27T07:32:00`,
+				"ld":     `1979-05-27`,
+				"lt":     `07:32:00.5`,
+				"list":   `["1979-05-27","1980-01-01"]`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.found == nil {
+				tt.found = map[string]string{}
+			}
+			if tt.unknown == nil {
+				tt.unknown = map[string]string{}
+			}
+
+			name := tempFile(t, tt.input)
+			p := toml.New(&name)
+
+			found, unknown, err := p.Provide(tt.awaited, zfg.ToString)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.found, found)
+			assert.Equal(t, tt.unknown, unknown)
+		})
+	}
+}
+
+func TestParse_Error(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{
+			name:  "duplicate key",
+			input: "a = 1\na = 2",
+			err:   "line 2: Key 'a' has already been defined",
+		},
+		{
+			name:  "duplicate table",
+			input: "[a]\nb = 1\n\n[a]\nc = 2",
+			err:   "line 4: Key 'a' has already been defined",
+		},
+		{
+			name:  "value is not a table",
+			input: "a = 1\n[a.b]",
+			err:   "line 2: Key 'a' was already created as a hash",
+		},
+		{
+			name:  "missing value",
+			input: "a = ",
+			err:   "line 1: unexpected EOF; expected value",
+		},
+		{
+			name:  "leading zero",
+			input: "\na = 01",
+			err:   "line 2: Invalid integer \"01\"",
+		},
+		{
+			name:  "integer out of range",
+			input: "n = 12345678901234567890",
+			err:   "line 1: 12345678901234567890 is out of range for int64",
+		},
+		{
+			name:  "underscore before exponent",
+			input: "f = 1_e5",
+			err:   "line 1:",
+		},
+		{
+			name:  "underscore after exponent",
+			input: "f = 1e_5",
+			err:   "line 1:",
+		},
+		{
+			name:  "leading underscore",
+			input: "n = _1",
+			err:   "line 1:",
+		},
+		{
+			name:  "trailing underscore",
+			input: "n = 1_",
+			err:   "line 1:",
+		},
+		{
+			name:  "double underscore",
+			input: "n = 1__0",
+			err:   "line 1:",
+		},
+		{
+			name:  "fraction without digits",
+			input: "f = 1.",
+			err:   "line 1:",
+		},
+		{
+			name:  "leading dot",
+			input: "f = .5",
+			err:   "line 1:",
+		},
+		{
+			name:  "unterminated string",
+			input: "a = \"text\nb = 1",
+			err:   "line 1: strings cannot contain newlines",
+		},
+		{
+			name:  "garbage after value",
+			input: "a = 1 b = 2",
+			err:   "line 1: expected a top-level item to end with a newline",
+		},
+		{
+			name:  "invalid escape",
+			input: "a = \"\\x\"",
+			err:   "line 1: invalid escape in string",
+		},
+		{
+			name:  "invalid date",
+			input: "d = 1979-13-27",
+			err:   "line 1:",
+		},
+		{
+			name:  "bare key with space",
+			input: "a b = 1",
+			err:   "line 1:",
+		},
+		{
+			name:  "control character",
+			input: "a = \"\x01\"",
+			err:   "line 1:",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := tempFile(t, tt.input)
+			p := toml.New(&name)
+
+			_, _, err := p.Provide(map[string]bool{}, zfg.ToString)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
+
+func tempFile(t *testing.T, data string) string {
+	f, err := os.CreateTemp("", "tmpfile-")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, f.Close())
+	})
+
+	_, err = f.WriteString(data)
+	require.NoError(t, err)
+
+	return f.Name()
+}
@@ -0,0 +1,21 @@
+package util
+
+import (
+	"regexp"
+	"strings"
+)
+
+var cleanRe = regexp.MustCompile(`[^A-Za-z0-9.]+`)
+
+// EnvName transforms the input string into an uppercase, underscore-separated
+// environment variable name by:
+// 1. Removing all characters except letters, digits, and dots.
+// 2. Converting to uppercase.
+// 3. Replacing dots with underscores.
+func EnvName(s string) string {
+	cleaned := cleanRe.ReplaceAllString(s, "")
+	upper := strings.ToUpper(cleaned)
+	envName := strings.ReplaceAll(upper, ".", "_")
+
+	return envName
+}
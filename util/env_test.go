@@ -0,0 +1,26 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvName(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"db.user", "DB_USER"},
+		{"app.api.key", "APP_API_KEY"},
+		{"camelCase.value", "CAMELCASE_VALUE"},
+		{"api-key.secret", "APIKEY_SECRET"},
+		{"under_score.value", "UNDERSCORE_VALUE"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, EnvName(tt.input))
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package util
+
+import (
+	"io"
+	"io/fs"
+	"os"
+)
+
+// Source is the content of a file provider: a file path, or data given to its
+// FromBytes, FromReader or FromFS constructor.
+type Source struct {
+	path *string
+	// read replaces reading the file at path, name is shown instead of the path.
+	read func() ([]byte, error)
+	name string
+}
+
+// FileSource returns a Source reading the file at path on every Load.
+func FileSource(path *string) Source {
+	return Source{path: path}
+}
+
+// BytesSource returns a Source of data, named "bytes".
+func BytesSource(data []byte) Source {
+	return Source{name: "bytes", read: func() ([]byte, error) { return data, nil }}
+}
+
+// ReaderSource returns a Source reading r on the first Load, named "reader".
+// Its content is reused afterwards.
+func ReaderSource(r io.Reader) Source {
+	return Source{name: "reader", read: ReadAll(r)}
+}
+
+// FSSource returns a Source reading the named file of fsys, e.g. an embed.FS.
+func FSSource(fsys fs.FS, name string) Source {
+	return Source{name: name, read: func() ([]byte, error) { return fs.ReadFile(fsys, name) }}
+}
+
+// Name returns the shortened file path, or the name of a source without a path.
+func (s Source) Name() string {
+	if s.read != nil {
+		return s.name
+	}
+
+	return ShortenPath(*s.path)
+}
+
+// Load returns the content of the source.
+func (s Source) Load() ([]byte, error) {
+	if s.read != nil {
+		return s.read()
+	}
+
+	return os.ReadFile(*s.path)
+}
+
+// ReadAll returns a function which reads r to the end on the first successful call
+// and returns the same content on later calls, so a provider created from a reader
+// can be used by several Parse calls.
+func ReadAll(r io.Reader) func() ([]byte, error) {
+	var data []byte
+	var done bool
+
+	return func() ([]byte, error) {
+		if done {
+			return data, nil
+		}
+
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+
+		data, done = b, true
+
+		return data, nil
+	}
+}
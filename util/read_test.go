@@ -0,0 +1,67 @@
+package util
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSource(t *testing.T) {
+	const data = "data"
+
+	path := filepath.Join(t.TempDir(), "app.conf")
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+
+	tests := []struct {
+		name string
+		src  Source
+		want string
+	}{
+		{name: "file", src: FileSource(&path), want: ShortenPath(path)},
+		{name: "bytes", src: BytesSource([]byte(data)), want: "bytes"},
+		{name: "reader", src: ReaderSource(strings.NewReader(data)), want: "reader"},
+		{name: "fs", src: FSSource(fstest.MapFS{"app.conf": {Data: []byte(data)}}, "app.conf"), want: "app.conf"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// a source is loaded on every Parse
+			for i := 0; i < 2; i++ {
+				got, err := tt.src.Load()
+				require.NoError(t, err)
+				assert.Equal(t, data, string(got))
+			}
+
+			assert.Equal(t, tt.want, tt.src.Name())
+		})
+	}
+
+	_, err := FSSource(fstest.MapFS{}, "app.conf").Load()
+	require.ErrorIs(t, err, fs.ErrNotExist)
+
+	missing := filepath.Join(t.TempDir(), "missing.conf")
+	_, err = FileSource(&missing).Load()
+	require.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestReadAll(t *testing.T) {
+	read := ReadAll(strings.NewReader("data"))
+
+	for i := 0; i < 2; i++ {
+		data, err := read()
+		require.NoError(t, err)
+		assert.Equal(t, "data", string(data))
+	}
+
+	errRead := errors.New("read failed")
+	_, err := ReadAll(iotest.ErrReader(errRead))()
+	require.ErrorIs(t, err, errRead)
+}
@@ -0,0 +1,323 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	nethttp "net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	zfg "github.com/chaindead/zerocfg"
+)
+
+const (
+	// DefaultAddress is the address of a local Vault server.
+	DefaultAddress = "http://127.0.0.1:8200"
+	// DefaultMount is the mount path of the KV v2 secrets engine.
+	DefaultMount = "secret"
+	// DefaultTimeout is the default timeout of a single request.
+	DefaultTimeout = 10 * time.Second
+)
+
+// ErrNotSecret is returned when a secret is read for an option not marked with zfg.Secret().
+var ErrNotSecret = errors.New("option is not secret")
+
+type Opt func(*Provider)
+
+// WithAddress returns an Opt that sets the address of the Vault server.
+// The address is read on Provide, so it can be set by other providers; an empty address means DefaultAddress.
+func WithAddress(address *string) Opt {
+	return func(p *Provider) {
+		p.address = address
+	}
+}
+
+// WithMount returns an Opt that sets the mount path of the KV v2 secrets engine.
+func WithMount(mount string) Opt {
+	return func(p *Provider) {
+		p.mount = strings.Trim(mount, "/")
+	}
+}
+
+// WithToken returns an Opt that authenticates with the token, read on Provide.
+func WithToken(token *string) Opt {
+	return func(p *Provider) {
+		p.token = token
+	}
+}
+
+// WithTokenFile returns an Opt that authenticates with the token stored in the file,
+// e.g. written by Vault Agent. The file is read on Provide.
+func WithTokenFile(path *string) Opt {
+	return func(p *Provider) {
+		p.tokenFile = path
+	}
+}
+
+// WithAppRole returns an Opt that logs in with the AppRole auth method on Provide.
+func WithAppRole(roleID, secretID *string) Opt {
+	return func(p *Provider) {
+		p.roleID = roleID
+		p.secretID = secretID
+	}
+}
+
+// AllowNonSecret returns an Opt that allows supplying values to options not marked with zfg.Secret().
+func AllowNonSecret() Opt {
+	return func(p *Provider) {
+		p.allowNonSecret = true
+	}
+}
+
+// WithTimeout returns an Opt that limits the duration of a single request.
+func WithTimeout(d time.Duration) Opt {
+	return func(p *Provider) {
+		p.timeout = d
+	}
+}
+
+// WithClient returns an Opt that sends requests with the client instead of http.DefaultClient.
+func WithClient(client *nethttp.Client) Opt {
+	return func(p *Provider) {
+		p.client = client
+	}
+}
+
+// Secret maps the fields of a KV v2 secret to options under a key prefix:
+// the field "password" of Secret{Path: "billing/db", Prefix: "db"} is the option "db.password".
+type Secret struct {
+	Path   string
+	Prefix string
+}
+
+// Provider reads secrets from the Vault KV v2 secrets engine.
+//
+// Secrets are read in order, later secrets override fields of earlier ones.
+// By default values are supplied only to options marked with zfg.Secret(),
+// other found options fail Provide with ErrNotSecret (see AllowNonSecret).
+// Unknown fields are reported without their values.
+type Provider struct {
+	secrets []Secret
+
+	address        *string
+	mount          string
+	token          *string
+	tokenFile      *string
+	roleID         *string
+	secretID       *string
+	allowNonSecret bool
+	timeout        time.Duration
+	client         *nethttp.Client
+
+	options map[string]zfg.OptionInfo
+	// sources maps found keys to the path of their secret.
+	sources map[string]string
+}
+
+// New creates a new Provider reading the secrets.
+//
+// Usage:
+//
+//	vault.New([]vault.Secret{{Path: "billing/db", Prefix: "db"}}, vault.WithToken(token))
+func New(secrets []Secret, opts ...Opt) *Provider {
+	p := &Provider{
+		secrets: secrets,
+		mount:   DefaultMount,
+		timeout: DefaultTimeout,
+		client:  nethttp.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// Type returns the type name of the parser.
+func (p *Provider) Type() string {
+	paths := make([]string, 0, len(p.secrets))
+	for _, s := range p.secrets {
+		paths = append(paths, p.mount+"/"+strings.Trim(s.Path, "/"))
+	}
+
+	return fmt.Sprintf("vault[%s]", strings.Join(paths, ","))
+}
+
+// Source returns the secret the value of a found key was read from.
+func (p *Provider) Source(key string) string {
+	path, ok := p.sources[key]
+	if !ok {
+		return ""
+	}
+
+	return fmt.Sprintf("vault[%s]", path)
+}
+
+// Inspect receives the registered options, which are used to refuse values for non-secret options.
+func (p *Provider) Inspect(options map[string]zfg.OptionInfo) {
+	p.options = options
+}
+
+func (p *Provider) Provide(awaited map[string]bool, conv func(any) string) (found, unknown map[string]string, err error) {
+	return p.ProvideContext(context.Background(), awaited, conv)
+}
+
+// ProvideContext is Provide cancelled with ctx.
+func (p *Provider) ProvideContext(ctx context.Context, awaited map[string]bool, conv func(any) string) (found, unknown map[string]string, err error) {
+	token, err := p.login(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	found, unknown = make(map[string]string), make(map[string]string)
+	p.sources = make(map[string]string)
+
+	for _, s := range p.secrets {
+		path := p.mount + "/" + strings.Trim(s.Path, "/")
+
+		data, err := p.read(ctx, token, s.Path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("vault %s: %w", path, err)
+		}
+
+		fields := make([]string, 0, len(data))
+		for field := range data {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+
+		for _, field := range fields {
+			key := field
+			if s.Prefix != "" {
+				key = s.Prefix + "." + field
+			}
+
+			if !awaited[key] {
+				unknown[key] = ""
+
+				continue
+			}
+
+			if !p.allowNonSecret && !p.options[key].Secret {
+				return nil, nil, fmt.Errorf("vault %s: field %q for option %q: %w", path, field, key, ErrNotSecret)
+			}
+
+			found[key] = ""
+			if v := data[field]; v != nil {
+				found[key] = conv(v)
+			}
+			p.sources[key] = path
+			delete(unknown, key)
+		}
+	}
+
+	return found, unknown, nil
+}
+
+// login returns the token to read secrets with.
+func (p *Provider) login(ctx context.Context) (string, error) {
+	switch {
+	case p.token != nil && *p.token != "":
+		return *p.token, nil
+	case p.tokenFile != nil && *p.tokenFile != "":
+		data, err := os.ReadFile(*p.tokenFile)
+		if err != nil {
+			return "", fmt.Errorf("vault: read token file: %w", err)
+		}
+
+		return strings.TrimSpace(string(data)), nil
+	case p.roleID != nil && *p.roleID != "":
+		var secretID string
+		if p.secretID != nil {
+			secretID = *p.secretID
+		}
+
+		body, err := json.Marshal(map[string]string{"role_id": *p.roleID, "secret_id": secretID})
+		if err != nil {
+			return "", err
+		}
+
+		var resp struct {
+			Auth struct {
+				ClientToken string `json:"client_token"`
+			} `json:"auth"`
+		}
+		if err = p.do(ctx, nethttp.MethodPost, "auth/approle/login", "", body, &resp); err != nil {
+			return "", fmt.Errorf("vault: approle login: %w", err)
+		}
+
+		return resp.Auth.ClientToken, nil
+	}
+
+	return "", errors.New("vault: no token, set one of WithToken, WithTokenFile or WithAppRole")
+}
+
+// read returns the fields of the latest version of a KV v2 secret.
+func (p *Provider) read(ctx context.Context, token, path string) (map[string]any, error) {
+	var resp struct {
+		Data struct {
+			Data map[string]any `json:"data"`
+		} `json:"data"`
+	}
+	if err := p.do(ctx, nethttp.MethodGet, p.mount+"/data/"+strings.Trim(path, "/"), token, nil, &resp); err != nil {
+		return nil, err
+	}
+
+	return resp.Data.Data, nil
+}
+
+func (p *Provider) do(ctx context.Context, method, path, token string, body []byte, out any) error {
+	address := DefaultAddress
+	if p.address != nil && *p.address != "" {
+		address = *p.address
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	req, err := nethttp.NewRequestWithContext(ctx, method, strings.TrimRight(address, "/")+"/v1/"+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != nethttp.StatusOK {
+		var e struct {
+			Errors []string `json:"errors"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&e)
+
+		err = fmt.Errorf("unexpected status %d %s", resp.StatusCode, nethttp.StatusText(resp.StatusCode))
+		if len(e.Errors) != 0 {
+			err = fmt.Errorf("%w: %s", err, strings.Join(e.Errors, "; "))
+		}
+		if resp.StatusCode == nethttp.StatusNotFound {
+			err = fmt.Errorf("%w: %w", err, fs.ErrNotExist)
+		}
+
+		return err
+	}
+
+	dec := json.NewDecoder(resp.Body)
+	dec.UseNumber()
+	if err = dec.Decode(out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,198 @@
+package vault_test
+
+import (
+	"encoding/json"
+	"io/fs"
+	nethttp "net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	zfg "github.com/chaindead/zerocfg"
+	"github.com/chaindead/zerocfg/vault"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	rootToken = "root-token"
+	roleID    = "role"
+	secretID  = "secret-id"
+)
+
+// fakeVault implements the KV v2 read endpoint and AppRole login.
+func fakeVault(t *testing.T, mount string, secrets map[string]map[string]any) string {
+	srv := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		if r.Method == nethttp.MethodPost && r.URL.Path == "/v1/auth/approle/login" {
+			var login map[string]string
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&login))
+			if login["role_id"] != roleID || login["secret_id"] != secretID {
+				reply(w, nethttp.StatusBadRequest, map[string]any{"errors": []string{"invalid role or secret ID"}})
+
+				return
+			}
+
+			reply(w, nethttp.StatusOK, map[string]any{"auth": map[string]any{"client_token": rootToken}})
+
+			return
+		}
+
+		if r.Header.Get("X-Vault-Token") != rootToken {
+			reply(w, nethttp.StatusForbidden, map[string]any{"errors": []string{"permission denied"}})
+
+			return
+		}
+
+		path, ok := strings.CutPrefix(r.URL.Path, "/v1/"+mount+"/data/")
+		data, exists := secrets[path]
+		if r.Method != nethttp.MethodGet || !ok || !exists {
+			reply(w, nethttp.StatusNotFound, map[string]any{"errors": []string{}})
+
+			return
+		}
+
+		reply(w, nethttp.StatusOK, map[string]any{
+			"data": map[string]any{
+				"data":     data,
+				"metadata": map[string]any{"version": 1},
+			},
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv.URL
+}
+
+func reply(w nethttp.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+var secrets = map[string]map[string]any{
+	"billing/db": {
+		"user":     "billing",
+		"password": "qwerty",
+		"extra":    "hidden",
+	},
+	"billing/api": {
+		"key":  "api-key",
+		"port": 8080,
+	},
+}
+
+func TestParse(t *testing.T) {
+	addr := fakeVault(t, "kv/team", secrets)
+	token := rootToken
+
+	p := vault.New([]vault.Secret{
+		{Path: "billing/db", Prefix: "db"},
+		{Path: "/billing/api/"},
+	}, vault.WithAddress(&addr), vault.WithMount("kv/team"), vault.WithToken(&token))
+
+	p.Inspect(map[string]zfg.OptionInfo{
+		"db.user":     {Type: "string", Secret: true},
+		"db.password": {Type: "string", Secret: true},
+		"key":         {Type: "string", Secret: true},
+		"port":        {Type: "int", Secret: true},
+	})
+
+	found, unknown, err := p.Provide(map[string]bool{
+		"db.user":     true,
+		"db.password": true,
+		"key":         true,
+		"port":        true,
+	}, zfg.ToString)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"db.user":     "billing",
+		"db.password": "qwerty",
+		"key":         "api-key",
+		"port":        "8080",
+	}, found)
+	assert.Equal(t, map[string]string{"db.extra": ""}, unknown)
+
+	assert.Equal(t, "vault[kv/team/billing/db,kv/team/billing/api]", p.Type())
+	assert.Equal(t, "vault[kv/team/billing/db]", p.Source("db.password"))
+	assert.Equal(t, "vault[kv/team/billing/api]", p.Source("key"))
+}
+
+func TestAuth(t *testing.T) {
+	addr := fakeVault(t, vault.DefaultMount, secrets)
+	options := map[string]zfg.OptionInfo{"db.password": {Type: "string", Secret: true}}
+	awaited := map[string]bool{"db.password": true}
+	secret := []vault.Secret{{Path: "billing/db", Prefix: "db"}}
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte(rootToken+"\n"), 0o600))
+
+	role, roleSecret, wrongSecret, emptyToken := roleID, secretID, "wrong", ""
+
+	tests := []struct {
+		name string
+		opt  vault.Opt
+		err  string
+	}{
+		{name: "token file", opt: vault.WithTokenFile(&tokenFile)},
+		{name: "approle", opt: vault.WithAppRole(&role, &roleSecret)},
+		{name: "approle failed", opt: vault.WithAppRole(&role, &wrongSecret), err: "approle login: unexpected status 400 Bad Request: invalid role or secret ID"},
+		{name: "no token", opt: vault.WithToken(&emptyToken), err: "vault: no token"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := vault.New(secret, vault.WithAddress(&addr), tt.opt)
+			p.Inspect(options)
+
+			found, _, err := p.Provide(awaited, zfg.ToString)
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, map[string]string{"db.password": "qwerty"}, found)
+		})
+	}
+}
+
+func TestNonSecret(t *testing.T) {
+	addr := fakeVault(t, vault.DefaultMount, secrets)
+	token := rootToken
+	secret := []vault.Secret{{Path: "billing/db", Prefix: "db"}}
+	options := map[string]zfg.OptionInfo{
+		"db.user":     {Type: "string"},
+		"db.password": {Type: "string", Secret: true},
+	}
+	awaited := map[string]bool{"db.user": true, "db.password": true}
+
+	p := vault.New(secret, vault.WithAddress(&addr), vault.WithToken(&token))
+	p.Inspect(options)
+	_, _, err := p.Provide(awaited, zfg.ToString)
+	require.ErrorIs(t, err, vault.ErrNotSecret)
+	require.ErrorContains(t, err, `option "db.user"`)
+
+	p = vault.New(secret, vault.WithAddress(&addr), vault.WithToken(&token), vault.AllowNonSecret())
+	p.Inspect(options)
+	found, _, err := p.Provide(awaited, zfg.ToString)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"db.user": "billing", "db.password": "qwerty"}, found)
+}
+
+func TestParse_Error(t *testing.T) {
+	addr := fakeVault(t, vault.DefaultMount, secrets)
+	token, wrongToken := rootToken, "wrong"
+
+	_, _, err := vault.New([]vault.Secret{{Path: "billing/missing"}}, vault.WithAddress(&addr), vault.WithToken(&token)).
+		Provide(map[string]bool{}, zfg.ToString)
+	require.ErrorIs(t, err, fs.ErrNotExist)
+	require.ErrorContains(t, err, "vault secret/billing/missing")
+
+	_, _, err = vault.New([]vault.Secret{{Path: "billing/db"}}, vault.WithAddress(&addr), vault.WithToken(&wrongToken)).
+		Provide(map[string]bool{}, zfg.ToString)
+	require.ErrorContains(t, err, "unexpected status 403 Forbidden: permission denied")
+}
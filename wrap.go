@@ -0,0 +1,306 @@
+package zerocfg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
+// wrapper forwards the optional interfaces of a wrapped provider.
+// Keys are passed through unchanged, wrappers renaming keys override its methods.
+type wrapper struct {
+	p Provider
+}
+
+func (w wrapper) Type() string {
+	return w.p.Type()
+}
+
+func (w wrapper) Source(key string) string {
+	if s, ok := w.p.(Sourcer); ok {
+		return s.Source(key)
+	}
+
+	return ""
+}
+
+func (w wrapper) Locate(key string) string {
+	if l, ok := w.p.(Locator); ok {
+		return l.Locate(key)
+	}
+
+	return ""
+}
+
+func (w wrapper) Inspect(options map[string]OptionInfo) {
+	if i, ok := w.p.(Inspector); ok {
+		i.Inspect(options)
+	}
+}
+
+type optional struct {
+	wrapper
+}
+
+// Optional returns a provider which reports errors matching fs.ErrNotExist,
+// like a missing file, as an empty result.
+//
+// Usage:
+//
+//	zfg.Parse(zfg.Optional(yaml.New(path)))
+func Optional(p Provider) Provider {
+	return optional{wrapper{p}}
+}
+
+func (o optional) Provide(awaited map[string]bool, conv func(any) string) (found, unknown map[string]string, err error) {
+	return o.ProvideContext(context.Background(), awaited, conv)
+}
+
+func (o optional) ProvideContext(ctx context.Context, awaited map[string]bool, conv func(any) string) (found, unknown map[string]string, err error) {
+	found, unknown, err = provide(ctx, o.p, awaited, conv)
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]string{}, map[string]string{}, nil
+	}
+
+	return found, unknown, err
+}
+
+type ignoreUnknown struct {
+	wrapper
+}
+
+// IgnoreUnknown returns a provider which never reports unknown keys.
+func IgnoreUnknown(p Provider) Provider {
+	return ignoreUnknown{wrapper{p}}
+}
+
+func (i ignoreUnknown) Provide(awaited map[string]bool, conv func(any) string) (found, unknown map[string]string, err error) {
+	return i.ProvideContext(context.Background(), awaited, conv)
+}
+
+func (i ignoreUnknown) ProvideContext(ctx context.Context, awaited map[string]bool, conv func(any) string) (found, unknown map[string]string, err error) {
+	found, _, err = provide(ctx, i.p, awaited, conv)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return found, map[string]string{}, nil
+}
+
+type firstOf struct {
+	ps []Provider
+	// used is the provider which succeeded in the last Provide.
+	used Provider
+}
+
+// FirstOf returns a provider using the result of the first provider that succeeds,
+// e.g. a local file when the config service is unavailable. Type and sources
+// name the provider used. If all providers fail, the errors of all of them are returned.
+//
+// Usage:
+//
+//	zfg.Parse(zfg.FirstOf(http.New(url), yaml.New(fallback)))
+func FirstOf(ps ...Provider) Provider {
+	return &firstOf{ps: ps}
+}
+
+func (f *firstOf) Type() string {
+	if f.used != nil {
+		return f.used.Type()
+	}
+
+	types := make([]string, 0, len(f.ps))
+	for _, p := range f.ps {
+		types = append(types, p.Type())
+	}
+
+	return fmt.Sprintf("first[%s]", strings.Join(types, ","))
+}
+
+func (f *firstOf) Provide(awaited map[string]bool, conv func(any) string) (found, unknown map[string]string, err error) {
+	return f.ProvideContext(context.Background(), awaited, conv)
+}
+
+func (f *firstOf) ProvideContext(ctx context.Context, awaited map[string]bool, conv func(any) string) (found, unknown map[string]string, err error) {
+	f.used = nil
+
+	errs := make([]error, 0, len(f.ps))
+	for _, p := range f.ps {
+		found, unknown, err = provide(ctx, p, awaited, conv)
+		if err == nil {
+			f.used = p
+
+			return found, unknown, nil
+		}
+
+		errs = append(errs, fmt.Errorf("%s: %w", p.Type(), err))
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	return nil, nil, errors.Join(errs...)
+}
+
+func (f *firstOf) Source(key string) string {
+	return wrapper{f.used}.Source(key)
+}
+
+func (f *firstOf) Locate(key string) string {
+	return wrapper{f.used}.Locate(key)
+}
+
+func (f *firstOf) Inspect(options map[string]OptionInfo) {
+	for _, p := range f.ps {
+		wrapper{p}.Inspect(options)
+	}
+}
+
+type mapped struct {
+	wrapper
+	fn func(key string) string
+
+	// names maps option keys found by the last Provide to keys of the wrapped provider.
+	names map[string]string
+}
+
+// Mapped returns a provider which renames keys: fn maps an option key or alias to
+// the key of the wrapped provider, an empty result hides the option from it.
+// Unknown keys are reported as named by the wrapped provider.
+//
+// Usage:
+//
+//	// read the db.url option from the DATABASE_URL variable
+//	zfg.Mapped(env.New(), func(key string) string {
+//	    if key == "db.url" {
+//	        return "database.url"
+//	    }
+//	    return key
+//	})
+func Mapped(p Provider, fn func(key string) string) Provider {
+	return &mapped{wrapper: wrapper{p}, fn: fn}
+}
+
+func (m *mapped) Provide(awaited map[string]bool, conv func(any) string) (found, unknown map[string]string, err error) {
+	return m.ProvideContext(context.Background(), awaited, conv)
+}
+
+func (m *mapped) ProvideContext(ctx context.Context, awaited map[string]bool, conv func(any) string) (found, unknown map[string]string, err error) {
+	inner := make(map[string]bool, len(awaited))
+	keys := make(map[string]string, len(awaited))
+	for k, isOption := range awaited {
+		name := m.fn(k)
+		if name == "" {
+			continue
+		}
+
+		inner[name] = isOption
+		keys[name] = k
+	}
+
+	innerFound, unknown, err := provide(ctx, m.p, inner, conv)
+	if err != nil {
+		return nil, nil, err
+	}
+	if unknown == nil {
+		unknown = make(map[string]string)
+	}
+
+	found = make(map[string]string, len(innerFound))
+	m.names = make(map[string]string, len(innerFound))
+	for name, v := range innerFound {
+		k, ok := keys[name]
+		if !ok {
+			unknown[name] = v
+
+			continue
+		}
+
+		found[k] = v
+		m.names[k] = name
+	}
+
+	return found, unknown, nil
+}
+
+func (m *mapped) name(key string) string {
+	if name, ok := m.names[key]; ok {
+		return name
+	}
+
+	return key
+}
+
+func (m *mapped) Source(key string) string {
+	return m.wrapper.Source(m.name(key))
+}
+
+func (m *mapped) Locate(key string) string {
+	return m.wrapper.Locate(m.name(key))
+}
+
+func (m *mapped) Inspect(options map[string]OptionInfo) {
+	inner := make(map[string]OptionInfo, len(options))
+	for k, o := range options {
+		if name := m.fn(k); name != "" {
+			inner[name] = o
+		}
+	}
+
+	m.wrapper.Inspect(inner)
+}
+
+// Prefixed returns a provider mounting the keys of p under prefix:
+// the key "db.host" of p is the option "app.db.host" for the prefix "app".
+// Options outside the prefix are hidden from p.
+//
+// Usage:
+//
+//	// DB_HOST of the billing env file is read as the option billing.db.host
+//	zfg.Prefixed(dotenv.New(path), "billing")
+func Prefixed(p Provider, prefix string) Provider {
+	prefix = strings.TrimSuffix(prefix, ".") + "."
+
+	fn := func(key string) string {
+		if !strings.HasPrefix(key, prefix) {
+			return ""
+		}
+
+		return strings.TrimPrefix(key, prefix)
+	}
+
+	return &prefixed{mapped: &mapped{wrapper: wrapper{p}, fn: fn}, prefix: prefix}
+}
+
+type prefixed struct {
+	*mapped
+	prefix string
+}
+
+func (p *prefixed) Provide(awaited map[string]bool, conv func(any) string) (found, unknown map[string]string, err error) {
+	return p.ProvideContext(context.Background(), awaited, conv)
+}
+
+func (p *prefixed) ProvideContext(ctx context.Context, awaited map[string]bool, conv func(any) string) (found, unknown map[string]string, err error) {
+	found, unknown, err = p.mapped.ProvideContext(ctx, awaited, conv)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	prefixedUnknown := make(map[string]string, len(unknown))
+	for k, v := range unknown {
+		prefixedUnknown[p.prefix+k] = v
+	}
+
+	return found, prefixedUnknown, nil
+}
+
+func (p *prefixed) Locate(key string) string {
+	if name, ok := p.names[key]; ok {
+		return p.wrapper.Locate(name)
+	}
+
+	return p.wrapper.Locate(strings.TrimPrefix(key, p.prefix))
+}
@@ -0,0 +1,161 @@
+package zerocfg
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type errProvider struct {
+	typ string
+	err error
+}
+
+func (e errProvider) Type() string {
+	return e.typ
+}
+
+func (e errProvider) Provide(map[string]bool, func(any) string) (found, unknown map[string]string, err error) {
+	return nil, nil, e.err
+}
+
+func Test_Optional(t *testing.T) {
+	c = testConfig()
+	port := Int("port", 80, "")
+
+	notExist := fmt.Errorf("read file: %w", fs.ErrNotExist)
+	require.NoError(t, Parse(Optional(errProvider{typ: "file", err: notExist})))
+	require.Equal(t, 80, *port)
+
+	c = testConfig()
+	Int("port", 80, "")
+
+	failed := errors.New("permission denied")
+	require.ErrorIs(t, Parse(Optional(errProvider{typ: "file", err: failed})), failed)
+
+	c = testConfig()
+	port = Int("port", 80, "")
+	require.NoError(t, Parse(Optional(newMock(map[string]any{"port": 8080}))))
+	require.Equal(t, 8080, *port)
+}
+
+func Test_IgnoreUnknown(t *testing.T) {
+	c = testConfig()
+	port := Int("port", 80, "")
+
+	err := Parse(IgnoreUnknown(newMock(map[string]any{"port": 8080, "unknown": 1})))
+	require.NoError(t, err)
+	require.Equal(t, 8080, *port)
+}
+
+func Test_FirstOf(t *testing.T) {
+	c = testConfig()
+	port := Int("port", 80, "")
+
+	remote := errProvider{typ: "remote", err: errors.New("connection refused")}
+	local := sourcerMock{
+		mockParser: newMock(map[string]any{"port": 8080}),
+		sources:    map[string]string{"port": "mock[local]"},
+	}
+
+	p := FirstOf(remote, local)
+	require.Equal(t, "first[remote,mock]", p.Type())
+
+	require.NoError(t, Parse(p))
+	require.Equal(t, 8080, *port)
+	require.Equal(t, "mock[local]", c.vs["port"].source())
+	require.Equal(t, mockType, p.Type())
+
+	c = testConfig()
+	Int("port", 80, "")
+
+	notExist := errProvider{typ: "file", err: fs.ErrNotExist}
+	err := Parse(FirstOf(remote, notExist))
+	require.ErrorIs(t, err, fs.ErrNotExist)
+	require.ErrorContains(t, err, "remote: connection refused")
+}
+
+func Test_Mapped(t *testing.T) {
+	c = testConfig()
+	url := Str("db.url", "", "", Secret())
+	port := Int("db.port", 0, "", Alias("p"))
+	hidden := Str("hidden", "default", "")
+
+	inspector := &inspectorMock{
+		mockParser: newMock(map[string]any{
+			"database.url": "postgres://",
+			"db.port":      5432,
+			"hidden":       "leaked",
+			"other":        1,
+		}),
+	}
+	p := Mapped(locatorMock{
+		mockParser: inspector.mockParser,
+		positions:  map[string]string{"database.url": "app.yaml:1:6", "other": "app.yaml:3:1"},
+	}, func(key string) string {
+		switch key {
+		case "db.url":
+			return "database.url"
+		case "hidden":
+			return ""
+		}
+
+		return key
+	})
+
+	err := Parse(p)
+	var pErr *UnknownPositionError
+	require.ErrorAs(t, err, &pErr)
+	require.Equal(t, map[string]string{"other": "app.yaml:3:1"}, pErr.Positions[mockType])
+	require.ElementsMatch(t, []string{"hidden", "other"}, pErr.UnknownFieldError[mockType])
+
+	require.Equal(t, "postgres://", *url)
+	require.Equal(t, 5432, *port)
+	require.Equal(t, "default", *hidden)
+	require.Equal(t, "app.yaml:1:6", p.(Locator).Locate("db.url"))
+
+	m := Mapped(inspector, func(key string) string {
+		if key == "db.url" {
+			return "database.url"
+		}
+		return ""
+	})
+	m.(Inspector).Inspect(map[string]OptionInfo{"db.url": {Type: "string", Secret: true}, "db.port": {Type: "int"}})
+	require.Equal(t, map[string]OptionInfo{"database.url": {Type: "string", Secret: true}}, inspector.options)
+}
+
+func Test_Prefixed(t *testing.T) {
+	c = testConfig()
+	host := Str("billing.db.host", "", "")
+	name := Str("name", "default", "")
+
+	p := Prefixed(locatorMock{
+		mockParser: newMock(map[string]any{"db.host": "localhost", "name": "leaked", "extra": 1}),
+		positions:  map[string]string{"db.host": "billing.env:1:1", "extra": "billing.env:3:1"},
+	}, "billing")
+
+	err := Parse(p)
+	var pErr *UnknownPositionError
+	require.ErrorAs(t, err, &pErr)
+	require.ElementsMatch(t, []string{"billing.extra", "billing.name"}, pErr.UnknownFieldError[mockType])
+	require.Equal(t, map[string]string{"billing.extra": "billing.env:3:1"}, pErr.Positions[mockType])
+
+	require.Equal(t, "localhost", *host)
+	require.Equal(t, "default", *name)
+	require.Equal(t, "billing.env:1:1", p.(Locator).Locate("billing.db.host"))
+}
+
+func Test_WrappersCompose(t *testing.T) {
+	c = testConfig()
+	host := Str("app.db.host", "", "")
+
+	err := Parse(
+		Optional(errProvider{typ: "missing", err: fs.ErrNotExist}),
+		IgnoreUnknown(Prefixed(newMock(map[string]any{"db.host": "localhost", "extra": 1}), "app")),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "localhost", *host)
+}
@@ -0,0 +1,69 @@
+package yaml
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// load returns the content of a file of the provider.
+func (p *Provider) load(file string) ([]byte, error) {
+	if p.read != nil {
+		return p.read()
+	}
+
+	return p.readFile(file)
+}
+
+// readFile reads a file from fsys if set, from the OS file system otherwise.
+func (p *Provider) readFile(name string) ([]byte, error) {
+	if p.fsys != nil {
+		return fs.ReadFile(p.fsys, name)
+	}
+
+	return os.ReadFile(name)
+}
+
+func (p *Provider) stat(name string) (fs.FileInfo, error) {
+	if p.fsys != nil {
+		return fs.Stat(p.fsys, name)
+	}
+
+	return os.Stat(name)
+}
+
+func (p *Provider) glob(pattern string) ([]string, error) {
+	if p.fsys != nil {
+		return fs.Glob(p.fsys, pattern)
+	}
+
+	return filepath.Glob(pattern)
+}
+
+// relative resolves the name against the directory of the file.
+func (p *Provider) relative(file, name string) string {
+	if p.fsys != nil {
+		return path.Join(path.Dir(file), name)
+	}
+
+	if filepath.IsAbs(name) {
+		return name
+	}
+
+	return filepath.Join(filepath.Dir(file), name)
+}
+
+// absPath returns a unique name of the file, used to detect include cycles.
+func (p *Provider) absPath(name string) string {
+	if p.fsys != nil {
+		return path.Clean(name)
+	}
+
+	abs, err := filepath.Abs(name)
+	if err != nil {
+		return filepath.Clean(name)
+	}
+
+	return abs
+}
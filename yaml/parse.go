@@ -1,78 +1,614 @@
 package yaml
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"strings"
 
+	zfg "github.com/chaindead/zerocfg"
 	"github.com/chaindead/zerocfg/util"
 	"gopkg.in/yaml.v3"
 )
 
+type Opt func(*Provider)
+
+// Optional returns an Opt that skips missing files and empty paths instead of failing.
+func Optional() Opt {
+	return func(p *Provider) {
+		p.optional = true
+	}
+}
+
+// SingleDocument returns an Opt that rejects files containing more than one document.
+// By default all documents of a stream are deep-merged in order.
+func SingleDocument() Opt {
+	return func(p *Provider) {
+		p.single = true
+	}
+}
+
+// NoTags returns an Opt that rejects the !include, !env and !file tags, for documents
+// from untrusted sources which must not read local files or the process environment.
+func NoTags() Opt {
+	return func(p *Provider) {
+		p.noTags = true
+	}
+}
+
+// WithProfile returns an Opt that selects a profile of the file.
+//
+// Profiles are defined in the top-level "profiles" mapping; the selected one is
+// deep-merged over the rest of the document. The profile is read on Provide, so
+// it can be set by higher priority providers (e.g. flags or env). Provide fails if
+// a profile is selected and none of the loaded files defines it.
+//
+// Usage:
+//
+//	profile := zfg.Str("profile", "", "configuration profile")
+//	zfg.Parse(env.New(), yaml.New(path, yaml.WithProfile(profile)))
+func WithProfile(profile *string) Opt {
+	return func(p *Provider) {
+		p.profile = profile
+	}
+}
+
+const profilesKey = "profiles"
+
+// origin is the place a node was read from.
+type origin struct {
+	file    string
+	profile string
+}
+
+// label returns the provenance of a value, e.g. "app.yaml#prod".
+func (o origin) label() string {
+	if o.profile == "" {
+		return util.ShortenPath(o.file)
+	}
+
+	return util.ShortenPath(o.file) + "#" + o.profile
+}
+
 type Provider struct {
-	path *string
+	paths    []*string
+	search   bool
+	optional bool
+	profile  *string
+	single   bool
+	noTags   bool
+
+	// fsys replaces the OS file system for files, globs and includes.
+	fsys fs.FS
+	// read replaces reading the single file of the provider.
+	read func() ([]byte, error)
+
+	// used holds the files read by the last Provide.
+	used []string
+	// hasProfile reports whether a file of the last Provide defined the selected profile.
+	hasProfile bool
 
 	conv    func(any) string
 	awaited map[string]bool
+	options map[string]zfg.OptionInfo
+
+	// origins maps every parsed node to the file it was read from.
+	origins map[*yaml.Node]origin
+	// sources maps found keys to the origin of their value.
+	sources map[string]origin
+	// positions maps found and unknown keys to the place they are defined.
+	positions map[string]string
 }
 
-func New(path *string) *Provider {
-	return &Provider{path: path}
+func New(path *string, opts ...Opt) *Provider {
+	return newProvider([]*string{path}, opts)
 }
 
+// FromBytes creates a Provider reading the given data.
+// Relative includes are resolved against the working directory.
+func FromBytes(data []byte, opts ...Opt) *Provider {
+	return from("bytes", func() ([]byte, error) { return data, nil }, opts)
+}
+
+// FromReader creates a Provider reading r on the first Provide; its content is reused afterwards.
+// Relative includes are resolved against the working directory.
+func FromReader(r io.Reader, opts ...Opt) *Provider {
+	return from("reader", util.ReadAll(r), opts)
+}
+
+// FromFS creates a Provider reading the named file of fsys, e.g. an embed.FS.
+// Includes and files referenced by tags are read from fsys too.
+//
+// Usage:
+//
+//	//go:embed defaults.yaml
+//	var defaults embed.FS
+//
+//	zfg.Parse(yaml.New(path), yaml.FromFS(defaults, "defaults.yaml"))
+func FromFS(fsys fs.FS, name string, opts ...Opt) *Provider {
+	p := newProvider([]*string{&name}, opts)
+	p.fsys = fsys
+
+	return p
+}
+
+func from(name string, read func() ([]byte, error), opts []Opt) *Provider {
+	p := newProvider([]*string{&name}, opts)
+	p.read = read
+
+	return p
+}
+
+func newProvider(paths []*string, opts []Opt) *Provider {
+	p := &Provider{paths: paths}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+func pointers(paths []string) []*string {
+	ps := make([]*string, 0, len(paths))
+	for _, path := range paths {
+		path := path
+		ps = append(ps, &path)
+	}
+
+	return ps
+}
+
+// Layered creates a Provider which deep-merges several YAML files in order,
+// so that later files override values of earlier ones.
+//
+// Each path may be a glob pattern; its matches are merged in lexical order and
+// a pattern without matches is skipped. Plain paths must exist.
+//
+// Usage:
+//
+//	yaml.Layered([]string{"/usr/share/app/defaults.yaml", "/etc/app/app.yaml", "/etc/app/conf.d/*.yaml"})
+func Layered(paths []string, opts ...Opt) *Provider {
+	return newProvider(pointers(paths), opts)
+}
+
+// Search creates a Provider which reads the first existing file of the candidate paths.
+// Environment variables in paths are expanded; a candidate referring to an unset
+// variable is skipped. If no file exists, Provide fails unless Optional is set.
+//
+// Usage:
+//
+//	yaml.Search([]string{"./app.yaml", "$XDG_CONFIG_HOME/app/app.yaml", "/etc/app/app.yaml"})
+func Search(paths []string, opts ...Opt) *Provider {
+	p := newProvider(pointers(paths), opts)
+	p.search = true
+
+	return p
+}
+
+// Type returns the type name of the parser. After Provide in search mode it names the file found.
 func (p *Provider) Type() string {
-	return fmt.Sprintf("yaml[%s]", util.ShortenPath(*p.path))
+	paths := make([]string, 0, len(p.paths))
+	for _, path := range p.paths {
+		paths = append(paths, util.ShortenPath(*path))
+	}
+
+	if p.search && len(p.used) != 0 {
+		paths = []string{util.ShortenPath(p.used[0])}
+	}
+
+	return fmt.Sprintf("yaml[%s]", strings.Join(paths, ","))
+}
+
+// Locate returns the file:line:column of a key read by the last Provide.
+// Found keys point to their value, unknown keys to the key itself.
+func (p *Provider) Locate(key string) string {
+	return p.positions[key]
+}
+
+// Used returns the files read by the last Provide, in merge order.
+func (p *Provider) Used() []string {
+	return p.used
+}
+
+// Source returns the file the value of a found key was read from.
+func (p *Provider) Source(key string) string {
+	o, ok := p.sources[key]
+	if !ok {
+		return ""
+	}
+
+	return fmt.Sprintf("yaml[%s]", o.label())
 }
 
 func (p *Provider) Provide(keys map[string]bool, conv func(any) string) (found, unknown map[string]string, err error) {
-	data, err := os.ReadFile(*p.path)
+	files, err := p.files()
 	if err != nil {
-		return nil, nil, fmt.Errorf("read yaml file: %w", err)
+		return nil, nil, err
 	}
 
 	p.conv = conv
 	p.awaited = keys
+	p.origins = make(map[*yaml.Node]origin)
+	p.sources = make(map[string]origin)
+	p.positions = make(map[string]string)
+	p.used = nil
+	p.hasProfile = false
+
+	settings := &yaml.Node{Kind: yaml.MappingNode}
+	for _, file := range files {
+		data, err := p.load(file)
+		if p.optional && errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("read yaml file: %w", err)
+		}
+		p.used = append(p.used, file)
+
+		doc, err := p.parse(file, data)
+		if err != nil {
+			return nil, nil, err
+		}
 
-	return p.parse(data)
+		p.merge(settings, doc)
+	}
+
+	if p.profile != nil && *p.profile != "" && len(p.used) != 0 && !p.hasProfile {
+		return nil, nil, fmt.Errorf("yaml profile %q is not defined in %s", *p.profile, strings.Join(p.used, ", "))
+	}
+
+	return p.flatten(settings)
 }
 
-func (p *Provider) parse(data []byte) (found, unknown map[string]string, err error) {
-	var settings map[string]any
-	if err = yaml.Unmarshal(data, &settings); err != nil {
-		return nil, nil, fmt.Errorf("unmarshal yaml: %w", err)
+// files expands glob patterns of the provider paths.
+func (p *Provider) files() ([]string, error) {
+	if p.search {
+		return p.find()
 	}
 
-	found, unknown = p.flatten(settings)
+	var files []string
+	for _, path := range p.paths {
+		if *path == "" {
+			if p.optional {
+				continue
+			}
 
-	return found, unknown, nil
+			return nil, fmt.Errorf("read yaml file: empty path")
+		}
+
+		if !isGlob(*path) {
+			files = append(files, *path)
+
+			continue
+		}
+
+		matches, err := p.glob(*path)
+		if err != nil {
+			return nil, fmt.Errorf("glob %q: %w", *path, err)
+		}
+
+		files = append(files, matches...)
+	}
+
+	return files, nil
+}
+
+// find returns the first existing file of the search candidates.
+func (p *Provider) find() ([]string, error) {
+	candidates := make([]string, 0, len(p.paths))
+	for _, path := range p.paths {
+		expanded, ok := expand(*path)
+		if !ok || expanded == "" {
+			continue
+		}
+
+		candidates = append(candidates, expanded)
+
+		info, err := p.stat(expanded)
+		if err == nil && !info.IsDir() {
+			return []string{expanded}, nil
+		}
+	}
+
+	if p.optional {
+		return nil, nil
+	}
+
+	return nil, fmt.Errorf("no yaml file found in %s: %w", strings.Join(candidates, ", "), fs.ErrNotExist)
+}
+
+// expand replaces environment variables in the path and reports whether all of them were set.
+func expand(path string) (string, bool) {
+	ok := true
+	expanded := os.Expand(path, func(name string) string {
+		v := os.Getenv(name)
+		if v == "" {
+			ok = false
+		}
+
+		return v
+	})
+
+	return expanded, ok
+}
+
+func isGlob(path string) bool {
+	return strings.ContainsAny(path, "*?[")
+}
+
+// parse decodes all documents of a file into a mapping node with tags resolved.
+// Documents are deep-merged in order, later documents override earlier ones.
+func (p *Provider) parse(file string, data []byte) (*yaml.Node, error) {
+	docs, err := p.documents(file, data, []string{p.absPath(file)})
+	if err != nil {
+		return nil, err
+	}
+
+	if p.single && len(docs) > 1 {
+		return nil, fmt.Errorf("unmarshal yaml %s: expected a single document, got %d", file, len(docs))
+	}
+
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	for i, doc := range docs {
+		doc = p.normalize(doc)
+		if doc.Kind == yaml.ScalarNode && doc.Tag == "!!null" {
+			continue
+		}
+
+		if doc.Kind != yaml.MappingNode {
+			if len(docs) > 1 {
+				return nil, fmt.Errorf("unmarshal yaml %s: document %d: top-level value must be a mapping", file, i+1)
+			}
+
+			return nil, fmt.Errorf("unmarshal yaml %s: top-level value must be a mapping", file)
+		}
+
+		p.merge(root, doc)
+	}
+
+	if err = p.applyProfile(root); err != nil {
+		return nil, err
+	}
+
+	return root, nil
 }
 
-func (p *Provider) flatten(settings map[string]any) (found, unknown map[string]string) {
+// applyProfile removes the profiles section from the root mapping and merges the selected profile over it.
+func (p *Provider) applyProfile(root *yaml.Node) error {
+	i := index(root, profilesKey)
+	if p.profile == nil || i < 0 {
+		return nil
+	}
+
+	profiles := root.Content[i+1]
+	root.Content = append(root.Content[:i:i], root.Content[i+2:]...)
+
+	if profiles.Kind != yaml.MappingNode {
+		return fmt.Errorf("%s: %q must be a mapping", p.position(profiles), profilesKey)
+	}
+
+	j := index(profiles, *p.profile)
+	if *p.profile == "" || j < 0 {
+		return nil
+	}
+
+	selected := profiles.Content[j+1]
+	if selected.Kind != yaml.MappingNode {
+		return fmt.Errorf("%s: profile %q must be a mapping", p.position(selected), *p.profile)
+	}
+
+	p.hasProfile = true
+	p.setProfile(selected, *p.profile)
+	p.merge(root, selected)
+
+	return nil
+}
+
+func (p *Provider) setProfile(n *yaml.Node, profile string) {
+	o := p.origins[n]
+	o.profile = profile
+	p.origins[n] = o
+
+	for _, child := range n.Content {
+		p.setProfile(child, profile)
+	}
+}
+
+// documents decodes the root nodes of all documents in a file, records the file
+// as the origin of their nodes and resolves tags. Roots may be of any kind.
+func (p *Provider) documents(file string, data []byte, chain []string) ([]*yaml.Node, error) {
+	var roots []*yaml.Node
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var doc yaml.Node
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unmarshal yaml %s: %w", file, err)
+		}
+
+		if len(doc.Content) == 0 {
+			continue
+		}
+
+		root := doc.Content[0]
+		p.setOrigin(root, file)
+
+		if err = p.resolve(root, file, chain); err != nil {
+			return nil, err
+		}
+
+		roots = append(roots, root)
+	}
+
+	return roots, nil
+}
+
+func (p *Provider) setOrigin(n *yaml.Node, file string) {
+	p.origins[n] = origin{file: file}
+	for _, child := range n.Content {
+		p.setOrigin(child, file)
+	}
+}
+
+// merge deep-merges the src mapping into dst. Mappings are merged key by key,
+// any other value of src replaces the value of dst.
+func (p *Provider) merge(dst, src *yaml.Node) {
+	p.origins[dst] = p.origins[src]
+
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		k, v := src.Content[i], src.Content[i+1]
+
+		j := index(dst, k.Value)
+		if j < 0 {
+			dst.Content = append(dst.Content, k, v)
+
+			continue
+		}
+
+		existing := dst.Content[j+1]
+		if existing.Kind == yaml.MappingNode && v.Kind == yaml.MappingNode {
+			p.merge(existing, v)
+
+			continue
+		}
+
+		dst.Content[j], dst.Content[j+1] = k, v
+	}
+}
+
+// index returns the position of the key in the mapping node, or -1.
+func index(m *yaml.Node, key string) int {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		if m.Content[i].Value == key {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// normalize returns a copy of the node with aliases resolved and merge keys (<<) expanded,
+// so that merging files never modifies nodes shared through anchors.
+func (p *Provider) normalize(n *yaml.Node) *yaml.Node {
+	if n.Kind == yaml.AliasNode {
+		return p.normalize(n.Alias)
+	}
+
+	cp := *n
+	cp.Content = nil
+	p.origins[&cp] = p.origins[n]
+
+	if n.Kind != yaml.MappingNode {
+		for _, child := range n.Content {
+			cp.Content = append(cp.Content, p.normalize(child))
+		}
+
+		return &cp
+	}
+
+	var merged []*yaml.Node
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, v := n.Content[i], p.normalize(n.Content[i+1])
+		if k.Tag != "!!merge" {
+			cp.Content = append(cp.Content, k, v)
+
+			continue
+		}
+
+		if v.Kind == yaml.SequenceNode {
+			merged = append(merged, v.Content...)
+		} else {
+			merged = append(merged, v)
+		}
+	}
+
+	// explicit keys and earlier merged mappings take precedence
+	for _, m := range merged {
+		for i := 0; i+1 < len(m.Content); i += 2 {
+			if index(&cp, m.Content[i].Value) < 0 {
+				cp.Content = append(cp.Content, m.Content[i], m.Content[i+1])
+			}
+		}
+	}
+
+	return &cp
+}
+
+func (p *Provider) flatten(settings *yaml.Node) (found, unknown map[string]string, err error) {
 	found, unknown = make(map[string]string), make(map[string]string)
 
-	p.flattenDFS(settings, "", found, unknown)
+	if err = p.flattenDFS(settings, "", found, unknown); err != nil {
+		return nil, nil, err
+	}
 
-	return found, unknown
+	return found, unknown, nil
 }
 
-func (p *Provider) flattenDFS(m map[string]any, prefix string, found, unknown map[string]string) {
-	for k, v := range m {
-		newKey := k
+func (p *Provider) flattenDFS(m *yaml.Node, prefix string, found, unknown map[string]string) error {
+	for i := 0; i+1 < len(m.Content); i += 2 {
+		k, v := m.Content[i], m.Content[i+1]
+
+		newKey := k.Value
 		if prefix != "" {
-			newKey = prefix + "." + k
+			newKey = prefix + "." + k.Value
 		}
 
-		if p.awaited[newKey] {
-			found[newKey] = p.conv(v)
+		if v.Kind == yaml.MappingNode && !p.awaited[newKey] {
+			if err := p.flattenDFS(v, newKey, found, unknown); err != nil {
+				return err
+			}
 
 			continue
 		}
 
-		if subMap, ok := v.(map[string]interface{}); ok {
-			p.flattenDFS(subMap, newKey, found, unknown)
+		// null leaves the option unset
+		if isNull(v) {
+			continue
+		}
+
+		if v.Kind == yaml.SequenceNode {
+			for _, e := range v.Content {
+				if isNull(e) {
+					return fmt.Errorf("key %q at %s: null sequence element", newKey, p.position(e))
+				}
+			}
+		}
+
+		var value any
+		if err := v.Decode(&value); err != nil {
+			return fmt.Errorf("decode key %q at %s: %w", newKey, p.position(v), err)
+		}
+
+		if p.awaited[newKey] {
+			if err := p.checkKind(newKey, v); err != nil {
+				return err
+			}
+
+			found[newKey] = p.conv(value)
+			p.sources[newKey] = p.origins[v]
+			p.positions[newKey] = p.position(v)
 
 			continue
 		}
 
-		unknown[newKey] = p.conv(v)
+		unknown[newKey] = p.conv(value)
+		p.positions[newKey] = p.position(k)
 	}
+
+	return nil
+}
+
+func isNull(n *yaml.Node) bool {
+	return n.Kind == yaml.ScalarNode && n.ShortTag() == "!!null"
+}
+
+func (p *Provider) position(n *yaml.Node) string {
+	return fmt.Sprintf("%s:%d:%d", p.origins[n].file, n.Line, n.Column)
 }
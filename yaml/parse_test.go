@@ -1,8 +1,13 @@
 package yaml_test
 
 import (
+	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"testing/fstest"
 
 	zfg "github.com/chaindead/zerocfg"
 	"github.com/chaindead/zerocfg/yaml"
@@ -110,6 +115,587 @@ func TestParse_Error(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestLayered(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "base.yaml"), `
+db:
+  host: localhost
+  port: 5432
+limits:
+  max: 10
+  min: 1
+tags: [a, b]`)
+	writeFile(t, filepath.Join(dir, "conf.d", "20-host.yaml"), `
+db:
+  host: host-b
+tags: [c]`)
+	writeFile(t, filepath.Join(dir, "conf.d", "10-site.yaml"), `
+db:
+  host: host-a
+  user: admin
+limits:
+  max: 20`)
+
+	p := yaml.Layered([]string{
+		filepath.Join(dir, "base.yaml"),
+		filepath.Join(dir, "conf.d", "*.yaml"),
+		filepath.Join(dir, "empty.d", "*.yaml"),
+	})
+
+	found, unknown, err := p.Provide(map[string]bool{
+		"db.host": true,
+		"db.port": true,
+		"limits":  true,
+		"tags":    true,
+	}, zfg.ToString)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"db.host": "host-b",
+		"db.port": "5432",
+		"limits":  `{"max":20,"min":1}`,
+		"tags":    `["c"]`,
+	}, found)
+	assert.Equal(t, map[string]string{"db.user": "admin"}, unknown)
+
+	assert.Contains(t, p.Source("db.host"), "20-host.yaml")
+	assert.Contains(t, p.Source("db.port"), "base.yaml")
+	assert.Contains(t, p.Source("limits"), "10-site.yaml")
+	assert.Contains(t, p.Source("tags"), "20-host.yaml")
+	assert.Empty(t, p.Source("db.user"))
+}
+
+func TestLayered_Error(t *testing.T) {
+	t.Run("missing plain path", func(t *testing.T) {
+		p := yaml.Layered([]string{filepath.Join(t.TempDir(), "missing.yaml")})
+
+		_, _, err := p.Provide(map[string]bool{}, zfg.ToString)
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+
+	t.Run("not a mapping", func(t *testing.T) {
+		name := tempFile(t, `[1, 2]`)
+		p := yaml.Layered([]string{name})
+
+		_, _, err := p.Provide(map[string]bool{}, zfg.ToString)
+		require.ErrorContains(t, err, "top-level value must be a mapping")
+	})
+}
+
+func TestOptional(t *testing.T) {
+	for name, path := range map[string]string{
+		"missing file": filepath.Join(t.TempDir(), "missing.yaml"),
+		"empty path":   "",
+	} {
+		t.Run(name, func(t *testing.T) {
+			p := yaml.New(&path, yaml.Optional())
+
+			found, unknown, err := p.Provide(map[string]bool{"a": true}, zfg.ToString)
+			require.NoError(t, err)
+			assert.Empty(t, found)
+			assert.Empty(t, unknown)
+			assert.Empty(t, p.Used())
+		})
+	}
+
+	t.Run("empty path without optional", func(t *testing.T) {
+		path := ""
+		p := yaml.New(&path)
+
+		_, _, err := p.Provide(map[string]bool{}, zfg.ToString)
+		require.ErrorContains(t, err, "empty path")
+	})
+}
+
+func TestSearch(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "xdg", "app", "app.yaml"), "source: xdg")
+	writeFile(t, filepath.Join(dir, "etc", "app.yaml"), "source: etc")
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "xdg"))
+	t.Setenv("ZEROCFG_UNSET", "")
+
+	p := yaml.Search([]string{
+		filepath.Join(dir, "app.yaml"),
+		"$ZEROCFG_UNSET/app/app.yaml",
+		"$XDG_CONFIG_HOME/app/app.yaml",
+		filepath.Join(dir, "etc", "app.yaml"),
+	})
+
+	found, _, err := p.Provide(map[string]bool{"source": true}, zfg.ToString)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"source": "xdg"}, found)
+	assert.Equal(t, []string{filepath.Join(dir, "xdg", "app", "app.yaml")}, p.Used())
+	assert.Contains(t, p.Type(), "app.yaml")
+	assert.NotContains(t, p.Type(), ",")
+}
+
+func TestSearch_NotFound(t *testing.T) {
+	candidates := []string{filepath.Join(t.TempDir(), "app.yaml")}
+
+	_, _, err := yaml.Search(candidates).Provide(map[string]bool{}, zfg.ToString)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	found, _, err := yaml.Search(candidates, yaml.Optional()).Provide(map[string]bool{}, zfg.ToString)
+	require.NoError(t, err)
+	assert.Empty(t, found)
+}
+
+func TestTags(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "app.yaml"), `
+db: !include teams/db.yaml
+name: !env ZEROCFG_YAML_NAME
+token: !file secrets/token`)
+	writeFile(t, filepath.Join(dir, "teams", "db.yaml"), `
+host: localhost
+pool: !include pool.yaml`)
+	writeFile(t, filepath.Join(dir, "teams", "pool.yaml"), `size: 10`)
+	writeFile(t, filepath.Join(dir, "secrets", "token"), "s3cr3t\n")
+	t.Setenv("ZEROCFG_YAML_NAME", "app")
+
+	name := filepath.Join(dir, "app.yaml")
+	p := yaml.New(&name)
+
+	found, _, err := p.Provide(map[string]bool{
+		"db.host":      true,
+		"db.pool.size": true,
+		"name":         true,
+		"token":        true,
+	}, zfg.ToString)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"db.host":      "localhost",
+		"db.pool.size": "10",
+		"name":         "app",
+		"token":        "s3cr3t",
+	}, found)
+	assert.Contains(t, p.Source("db.pool.size"), "pool.yaml")
+	assert.Contains(t, p.Source("name"), "app.yaml")
+}
+
+func TestTags_Error(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		err   []string
+	}{
+		{
+			name: "cycle",
+			files: map[string]string{
+				"app.yaml": "a: !include a/a.yaml",
+				"a/a.yaml": "b: !include ../b.yaml",
+				"b.yaml":   "c: !include app.yaml",
+			},
+			err: []string{"app.yaml:1: !include a/a.yaml", "a.yaml:1: !include ../b.yaml", "b.yaml:1: !include app.yaml: include cycle"},
+		},
+		{
+			name: "missing include",
+			files: map[string]string{
+				"app.yaml": "\na: !include a.yaml",
+				"a.yaml":   "b: !include missing.yaml",
+			},
+			err: []string{"app.yaml:2: !include a.yaml", "a.yaml:1: !include missing.yaml: read yaml file"},
+		},
+		{
+			name: "unset env",
+			files: map[string]string{
+				"app.yaml": "a: !env ZEROCFG_YAML_UNSET",
+			},
+			err: []string{"app.yaml:1: !env ZEROCFG_YAML_UNSET: variable is not set"},
+		},
+		{
+			name: "missing file",
+			files: map[string]string{
+				"app.yaml": "a: !file missing",
+			},
+			err: []string{"app.yaml:1: !file missing"},
+		},
+		{
+			name: "not a scalar",
+			files: map[string]string{
+				"app.yaml": "a: !include [a.yaml]",
+			},
+			err: []string{"app.yaml:1: !include expects a scalar value"},
+		},
+		{
+			name: "multi-document include",
+			files: map[string]string{
+				"app.yaml": "a: !include a.yaml",
+				"a.yaml":   "b: 1\n---\nb: 2",
+			},
+			err: []string{"app.yaml:1: !include a.yaml: expected a single document, got 2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, data := range tt.files {
+				writeFile(t, filepath.Join(dir, name), data)
+			}
+
+			name := filepath.Join(dir, "app.yaml")
+			_, _, err := yaml.New(&name).Provide(map[string]bool{}, zfg.ToString)
+			require.Error(t, err)
+
+			for _, e := range tt.err {
+				assert.Contains(t, err.Error(), e)
+			}
+		})
+	}
+}
+
+func TestNoTags(t *testing.T) {
+	t.Setenv("ZEROCFG_YAML_SECRET", "secret")
+
+	for _, data := range []string{"a: !env ZEROCFG_YAML_SECRET", "a: !file /etc/hostname", "a: !include other.yaml"} {
+		_, _, err := yaml.FromBytes([]byte(data), yaml.NoTags()).Provide(map[string]bool{"a": true}, zfg.ToString)
+		require.ErrorContains(t, err, "is not allowed, tags are disabled")
+	}
+
+	found, _, err := yaml.FromBytes([]byte("a: !!str 1"), yaml.NoTags()).Provide(map[string]bool{"a": true}, zfg.ToString)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"a": "1"}, found)
+}
+
+func TestLocate(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "app.yaml"), `
+db:
+  host: localhost
+  prt: 5432
+  pool: !include pool.yaml`)
+	writeFile(t, filepath.Join(dir, "pool.yaml"), `
+size: 10
+idle: 1`)
+
+	name := filepath.Join(dir, "app.yaml")
+	p := yaml.New(&name)
+
+	_, _, err := p.Provide(map[string]bool{"db.host": true, "db.pool.size": true}, zfg.ToString)
+	require.NoError(t, err)
+
+	assert.Equal(t, name+":3:9", p.Locate("db.host"))
+	assert.Equal(t, name+":4:3", p.Locate("db.prt"))
+	assert.Equal(t, filepath.Join(dir, "pool.yaml")+":2:7", p.Locate("db.pool.size"))
+	assert.Equal(t, filepath.Join(dir, "pool.yaml")+":3:1", p.Locate("db.pool.idle"))
+	assert.Empty(t, p.Locate("missing"))
+}
+
+func TestKinds(t *testing.T) {
+	options := map[string]zfg.OptionInfo{
+		"name":   {Type: "string"},
+		"port":   {Type: "int"},
+		"tags":   {Type: "strings"},
+		"limits": {Type: "map"},
+		"custom": {Type: "custom"},
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		found map[string]string
+		err   string
+	}{
+		{
+			name: "matching",
+			input: `
+name: 42
+port: 8080
+tags: [a, b]
+limits: {max: 1}
+custom: [1, 2]`,
+			found: map[string]string{
+				"name":   "42",
+				"port":   "8080",
+				"tags":   `["a","b"]`,
+				"limits": `{"max":1}`,
+				"custom": `[1,2]`,
+			},
+		},
+		{
+			name: "null",
+			input: `
+name:
+port: ~
+limits: null
+other: ~
+tags: [a]`,
+			found: map[string]string{
+				"tags": `["a"]`,
+			},
+		},
+		{
+			name:  "null in sequence",
+			input: "tags: [a, ~]",
+			err:   `key "tags" at %s:1:11: null sequence element`,
+		},
+		{
+			name:  "sequence for scalar",
+			input: "name: [a, b]",
+			err:   `key "name" at %s:1:7: expected scalar for string option, got sequence`,
+		},
+		{
+			name:  "mapping for scalar",
+			input: "port:\n  value: 1",
+			err:   `key "port" at %s:2:3: expected scalar for int option, got mapping`,
+		},
+		{
+			name:  "scalar for sequence",
+			input: "tags: a",
+			err:   `key "tags" at %s:1:7: expected sequence for strings option, got scalar`,
+		},
+		{
+			name:  "sequence for mapping",
+			input: "limits: [1]",
+			err:   `key "limits" at %s:1:9: expected mapping for map option, got sequence`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := tempFile(t, tt.input)
+			p := yaml.New(&name)
+			p.Inspect(options)
+
+			awaited := make(map[string]bool)
+			for k := range options {
+				awaited[k] = true
+			}
+
+			found, _, err := p.Provide(awaited, zfg.ToString)
+			if tt.err != "" {
+				require.EqualError(t, err, fmt.Sprintf(tt.err, name))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.found, found)
+		})
+	}
+}
+
+func TestParse_Anchors(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "a.yaml"), `
+defaults: &defaults
+  timeout: 1s
+  retries: 3
+service:
+  <<: *defaults
+  retries: 5
+other: *defaults`)
+	writeFile(t, filepath.Join(dir, "b.yaml"), `
+service:
+  timeout: 2s`)
+
+	p := yaml.Layered([]string{filepath.Join(dir, "a.yaml"), filepath.Join(dir, "b.yaml")})
+
+	found, _, err := p.Provide(map[string]bool{
+		"service.timeout": true,
+		"service.retries": true,
+		"other.timeout":   true,
+	}, zfg.ToString)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"service.timeout": "2s",
+		"service.retries": "5",
+		"other.timeout":   "1s",
+	}, found)
+}
+
+func TestProfiles(t *testing.T) {
+	path := tempFile(t, `
+db:
+  host: localhost
+  port: 5432
+log: debug
+profiles:
+  prod:
+    db:
+      host: db.prod
+    log: warn
+  dev:
+    db:
+      port: 15432`)
+
+	awaited := map[string]bool{
+		"db.host": true,
+		"db.port": true,
+		"log":     true,
+	}
+
+	tests := []struct {
+		name    string
+		profile string
+		want    map[string]string
+	}{
+		{
+			name:    "prod",
+			profile: "prod",
+			want:    map[string]string{"db.host": "db.prod", "db.port": "5432", "log": "warn"},
+		},
+		{
+			name:    "dev",
+			profile: "dev",
+			want:    map[string]string{"db.host": "localhost", "db.port": "15432", "log": "debug"},
+		},
+		{
+			name:    "no profile",
+			profile: "",
+			want:    map[string]string{"db.host": "localhost", "db.port": "5432", "log": "debug"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := tt.profile
+			p := yaml.New(&path, yaml.WithProfile(&profile))
+
+			found, unknown, err := p.Provide(awaited, zfg.ToString)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, found)
+			assert.Empty(t, unknown)
+		})
+	}
+
+	profile := "prod"
+	p := yaml.New(&path, yaml.WithProfile(&profile))
+	_, _, err := p.Provide(awaited, zfg.ToString)
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasSuffix(p.Source("db.host"), "#prod]"), p.Source("db.host"))
+	assert.NotContains(t, p.Source("db.port"), "#")
+	assert.Contains(t, p.Locate("log"), path)
+}
+
+func TestProfiles_Layered(t *testing.T) {
+	base := tempFile(t, "log: debug")
+	override := tempFile(t, "profiles:\n  prod:\n    log: warn")
+	profile := "prod"
+
+	found, _, err := yaml.Layered([]string{base, override}, yaml.WithProfile(&profile)).Provide(map[string]bool{"log": true}, zfg.ToString)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"log": "warn"}, found)
+
+	missing := filepath.Join(t.TempDir(), "missing.yaml")
+	_, _, err = yaml.New(&missing, yaml.Optional(), yaml.WithProfile(&profile)).Provide(map[string]bool{}, zfg.ToString)
+	require.NoError(t, err)
+}
+
+func TestProfiles_Error(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{name: "profiles not mapping", data: "profiles: [prod]", want: `"profiles" must be a mapping`},
+		{name: "profile not mapping", data: "profiles:\n  prod: 1", want: `profile "prod" must be a mapping`},
+		{name: "missing profile", data: "log: debug\nprofiles:\n  prd:\n    log: warn", want: `yaml profile "prod" is not defined in `},
+		{name: "no profiles", data: "log: debug", want: `yaml profile "prod" is not defined in `},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := tempFile(t, tt.data)
+			profile := "prod"
+
+			_, _, err := yaml.New(&path, yaml.WithProfile(&profile)).Provide(map[string]bool{}, zfg.ToString)
+			require.ErrorContains(t, err, tt.want)
+		})
+	}
+}
+
+func TestDocuments(t *testing.T) {
+	path := tempFile(t, `
+db:
+  host: localhost
+  port: 5432
+---
+---
+db:
+  host: db.local
+name: app
+---
+name: service`)
+
+	found, unknown, err := yaml.New(&path).Provide(map[string]bool{
+		"db.host": true,
+		"db.port": true,
+		"name":    true,
+	}, zfg.ToString)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"db.host": "db.local", "db.port": "5432", "name": "service"}, found)
+	assert.Empty(t, unknown)
+
+	_, _, err = yaml.New(&path, yaml.SingleDocument()).Provide(map[string]bool{}, zfg.ToString)
+	require.ErrorContains(t, err, "expected a single document, got 4")
+
+	path = tempFile(t, "a: 1\n---\n- b")
+	_, _, err = yaml.New(&path).Provide(map[string]bool{}, zfg.ToString)
+	require.ErrorContains(t, err, "document 2: top-level value must be a mapping")
+}
+
+func TestFromSources(t *testing.T) {
+	data := "db:\n  host: localhost\n  port: 5432"
+	awaited := map[string]bool{"db.host": true, "db.port": true}
+	want := map[string]string{"db.host": "localhost", "db.port": "5432"}
+
+	tests := []struct {
+		name     string
+		provider *yaml.Provider
+		typ      string
+	}{
+		{name: "bytes", provider: yaml.FromBytes([]byte(data)), typ: "yaml[bytes]"},
+		{name: "reader", provider: yaml.FromReader(strings.NewReader(data)), typ: "yaml[reader]"},
+		{name: "fs", provider: yaml.FromFS(fstest.MapFS{"app.yaml": {Data: []byte(data)}}, "app.yaml"), typ: "yaml[app.yaml]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// providers are reusable across Parse calls
+			for i := 0; i < 2; i++ {
+				found, unknown, err := tt.provider.Provide(awaited, zfg.ToString)
+				require.NoError(t, err)
+				assert.Equal(t, want, found)
+				assert.Empty(t, unknown)
+			}
+
+			assert.Equal(t, tt.typ, tt.provider.Type())
+		})
+	}
+}
+
+func TestFromFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"conf.d/10-base.yaml":  {Data: []byte("db: !include db/db.yaml\ntoken: !file secrets/token")},
+		"conf.d/20-site.yaml":  {Data: []byte("db:\n  port: 6543")},
+		"conf.d/db/db.yaml":    {Data: []byte("host: localhost\nport: 5432")},
+		"conf.d/secrets/token": {Data: []byte("secret\n")},
+	}
+
+	p := yaml.FromFS(fsys, "conf.d/*.yaml")
+	found, _, err := p.Provide(map[string]bool{"db.host": true, "db.port": true, "token": true}, zfg.ToString)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"db.host": "localhost", "db.port": "6543", "token": "secret"}, found)
+	assert.Equal(t, []string{"conf.d/10-base.yaml", "conf.d/20-site.yaml"}, p.Used())
+	assert.Equal(t, "conf.d/db/db.yaml:1:7", p.Locate("db.host"))
+
+	_, _, err = yaml.FromFS(fsys, "missing.yaml").Provide(map[string]bool{}, zfg.ToString)
+	require.ErrorIs(t, err, fs.ErrNotExist)
+
+	_, _, err = yaml.FromFS(fsys, "missing.yaml", yaml.Optional()).Provide(map[string]bool{}, zfg.ToString)
+	require.NoError(t, err)
+}
+
+func writeFile(t *testing.T, path, data string) {
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+}
+
 func tempFile(t *testing.T, data string) string {
 	f, err := os.CreateTemp("", "tmpfile-")
 	require.NoError(t, err)
@@ -0,0 +1,105 @@
+package yaml
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// tagInclude replaces the node with the content of another YAML file.
+	tagInclude = "!include"
+	// tagEnv replaces the node with the value of an environment variable.
+	tagEnv = "!env"
+	// tagFile replaces the node with the trimmed content of a file.
+	tagFile = "!file"
+)
+
+// resolve replaces nodes tagged with !include, !env and !file in place.
+// Relative paths are resolved against the directory of the including file;
+// chain holds the absolute paths of the files being included, to detect cycles.
+func (p *Provider) resolve(n *yaml.Node, file string, chain []string) error {
+	switch n.Tag {
+	case tagInclude, tagEnv, tagFile:
+		if p.noTags {
+			return fmt.Errorf("%s:%d: %s is not allowed, tags are disabled", file, n.Line, n.Tag)
+		}
+		if n.Kind != yaml.ScalarNode {
+			return fmt.Errorf("%s:%d: %s expects a scalar value", file, n.Line, n.Tag)
+		}
+	}
+
+	switch n.Tag {
+	case tagInclude:
+		return p.include(n, file, chain)
+	case tagEnv:
+		v, ok := os.LookupEnv(n.Value)
+		if !ok {
+			return fmt.Errorf("%s:%d: %s %s: variable is not set", file, n.Line, tagEnv, n.Value)
+		}
+
+		setString(n, v)
+	case tagFile:
+		data, err := p.readFile(p.relative(file, n.Value))
+		if err != nil {
+			return fmt.Errorf("%s:%d: %s %s: %w", file, n.Line, tagFile, n.Value, err)
+		}
+
+		setString(n, strings.TrimSpace(string(data)))
+	}
+
+	if n.Kind == yaml.AliasNode {
+		return nil
+	}
+
+	for _, child := range n.Content {
+		if err := p.resolve(child, file, chain); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *Provider) include(n *yaml.Node, file string, chain []string) error {
+	target := p.relative(file, n.Value)
+
+	abs := p.absPath(target)
+	for _, included := range chain {
+		if included == abs {
+			return fmt.Errorf("%s:%d: %s %s: include cycle: %s -> %s", file, n.Line, tagInclude, n.Value, strings.Join(chain, " -> "), abs)
+		}
+	}
+
+	data, err := p.readFile(target)
+	if err != nil {
+		return fmt.Errorf("%s:%d: %s %s: read yaml file: %w", file, n.Line, tagInclude, n.Value, err)
+	}
+
+	roots, err := p.documents(target, data, append(chain[:len(chain):len(chain)], abs))
+	if err != nil {
+		return fmt.Errorf("%s:%d: %s %s: %w", file, n.Line, tagInclude, n.Value, err)
+	}
+
+	if len(roots) > 1 {
+		return fmt.Errorf("%s:%d: %s %s: expected a single document, got %d", file, n.Line, tagInclude, n.Value, len(roots))
+	}
+
+	root := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null"}
+	if len(roots) == 1 {
+		root = roots[0]
+	}
+
+	*n = *root
+	p.origins[n] = origin{file: target}
+
+	return nil
+}
+
+func setString(n *yaml.Node, v string) {
+	n.Tag = "!!str"
+	n.Value = v
+	n.Style = 0
+}
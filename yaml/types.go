@@ -0,0 +1,61 @@
+package yaml
+
+import (
+	"fmt"
+
+	zfg "github.com/chaindead/zerocfg"
+	"gopkg.in/yaml.v3"
+)
+
+// kinds maps option types of zerocfg to the YAML node kind they accept.
+// Custom option types are not checked.
+var kinds = map[string]yaml.Kind{
+	"map": yaml.MappingNode,
+
+	"bools":     yaml.SequenceNode,
+	"durations": yaml.SequenceNode,
+	"floats32":  yaml.SequenceNode,
+	"floats64":  yaml.SequenceNode,
+	"ints":      yaml.SequenceNode,
+	"ips":       yaml.SequenceNode,
+	"strings":   yaml.SequenceNode,
+
+	"bool":     yaml.ScalarNode,
+	"duration": yaml.ScalarNode,
+	"float32":  yaml.ScalarNode,
+	"float64":  yaml.ScalarNode,
+	"int":      yaml.ScalarNode,
+	"int32":    yaml.ScalarNode,
+	"int64":    yaml.ScalarNode,
+	"ip":       yaml.ScalarNode,
+	"string":   yaml.ScalarNode,
+	"time":     yaml.ScalarNode,
+	"uint":     yaml.ScalarNode,
+	"uint32":   yaml.ScalarNode,
+	"uint64":   yaml.ScalarNode,
+}
+
+var kindNames = map[yaml.Kind]string{
+	yaml.MappingNode:  "mapping",
+	yaml.SequenceNode: "sequence",
+	yaml.ScalarNode:   "scalar",
+}
+
+// Inspect receives the option types, which are used to reject values of the wrong
+// structure: a sequence or mapping for a scalar option, a scalar for a list, and so on.
+// Any scalar is accepted for scalar options, e.g. a number for a string option.
+func (p *Provider) Inspect(options map[string]zfg.OptionInfo) {
+	p.options = options
+}
+
+func (p *Provider) checkKind(key string, v *yaml.Node) error {
+	typ := p.options[key].Type
+
+	expected, ok := kinds[typ]
+	if !ok || v.Kind == expected {
+		return nil
+	}
+
+	return fmt.Errorf("key %q at %s: expected %s for %s option, got %s",
+		key, p.position(v), kindNames[expected], typ, kindNames[v.Kind])
+}
@@ -43,7 +43,9 @@ func Any[T any](name string, defVal T, desc string, create func(T, *T) Value, op
 
 	p := new(T)
 	*p = defVal
-	c.add(name, create(defVal, p), desc, opts...)
+	v := create(defVal, p)
+	n := c.add(name, v, desc, opts...)
+	register(n, p)
 
 	return p
 }
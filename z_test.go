@@ -138,6 +138,12 @@ func Test_ValueOk(t *testing.T) {
 				return regSource(Durs, []time.Duration{time.Second, 2 * time.Minute, 3 * time.Hour})
 			},
 		},
+		{
+			varType: "time",
+			init: func() (func() any, any, map[string]any) {
+				return regSource(Time, time.Date(1979, 5, 27, 7, 32, 0, 999999, time.UTC))
+			},
+		},
 		{
 			varType: "ip",
 			init: func() (func() any, any, map[string]any) {
@@ -230,3 +236,28 @@ func Test_IPSlice(t *testing.T) {
 	require.Equal(t, "10.0.0.1", (*ips)[0].String())
 	require.Equal(t, "10.0.0.2", (*ips)[1].String())
 }
+
+func Test_TimeLayouts(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected time.Time
+	}{
+		{"1979-05-27T07:32:00-07:00", time.Date(1979, 5, 27, 7, 32, 0, 0, time.FixedZone("", -7*60*60))},
+		{"1979-05-27T07:32:00.5", time.Date(1979, 5, 27, 7, 32, 0, 5e8, time.Local)},
+		{"1979-05-27 07:32:00", time.Date(1979, 5, 27, 7, 32, 0, 0, time.Local)},
+		{"1979-05-27", time.Date(1979, 5, 27, 0, 0, 0, 0, time.Local)},
+		{"07:32:00", time.Date(0, 1, 1, 7, 32, 0, 0, time.Local)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var v time.Time
+			err := newTimeValue(time.Time{}, &v).Set(tt.input)
+			require.NoError(t, err)
+			require.True(t, tt.expected.Equal(v), "expected %s, got %s", tt.expected, v)
+		})
+	}
+
+	var v time.Time
+	require.Error(t, newTimeValue(time.Time{}, &v).Set("yesterday"))
+}
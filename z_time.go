@@ -0,0 +1,59 @@
+package zerocfg
+
+import (
+	"fmt"
+	"time"
+)
+
+// timeLayouts are accepted by the Time option, in addition to RFC 3339.
+// Values without a time zone are interpreted in the local time zone.
+var timeLayouts = []string{
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"15:04:05",
+	"2006-01-02 15:04:05 -0700 MST",
+}
+
+type timeValue time.Time
+
+func newTimeValue(val time.Time, p *time.Time) Value {
+	*p = val
+	return (*timeValue)(p)
+}
+
+func (t *timeValue) Set(val string) error {
+	parsed, err := time.Parse(time.RFC3339Nano, val)
+	if err == nil {
+		*t = timeValue(parsed)
+		return nil
+	}
+
+	for _, layout := range timeLayouts {
+		parsed, err = time.ParseInLocation(layout, val, time.Local)
+		if err == nil {
+			*t = timeValue(parsed)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("time %q is not in RFC 3339 format", val)
+}
+
+func (t *timeValue) Type() string {
+	return "time"
+}
+
+func (t *timeValue) String() string {
+	return time.Time(*t).Format(time.RFC3339Nano)
+}
+
+// Time registers a time.Time configuration option and returns a pointer to its value.
+// Values are parsed as RFC 3339; local date-times, dates and times are also accepted.
+//
+// Usage:
+//
+//	since := zerocfg.Time("since", time.Time{}, "start of the period")
+func Time(name string, defVal time.Time, desc string, opts ...OptNode) *time.Time {
+	return Any(name, defVal, desc, newTimeValue, opts...)
+}
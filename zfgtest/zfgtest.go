@@ -0,0 +1,75 @@
+// Package zfgtest helps to test code depending on zerocfg options without
+// touching os.Args, the environment or files.
+package zfgtest
+
+import (
+	"strings"
+	"testing"
+
+	zfg "github.com/chaindead/zerocfg"
+)
+
+// Source is the source of values set by Set.
+const Source = "zfgtest"
+
+// Map is a provider of static values keyed by option names or aliases.
+// Values are converted the same way as values of other providers.
+//
+// Usage:
+//
+//	err := zfg.Parse(zfgtest.Map{"db.port": 5432, "hosts": []string{"a", "b"}})
+type Map map[string]any
+
+// Type returns the type name of the parser.
+func (m Map) Type() string {
+	return "map"
+}
+
+func (m Map) Provide(awaited map[string]bool, conv func(any) string) (found, unknown map[string]string, err error) {
+	found, unknown = make(map[string]string), make(map[string]string)
+	for k, v := range m {
+		if _, ok := awaited[k]; ok {
+			found[k] = conv(v)
+		} else {
+			unknown[k] = conv(v)
+		}
+	}
+
+	return found, unknown, nil
+}
+
+// Reset restores the default values of all options and allows zfg.Parse to be called again.
+// It must not run concurrently with tests reading options.
+func Reset() {
+	zfg.Reset()
+}
+
+// Set overrides the value of an option or alias for the duration of the test.
+// The previous value and source are restored by t.Cleanup; the source of the
+// overridden option is Source. Parallel tests may override different options,
+// but not the same one.
+//
+// Usage:
+//
+//	zfgtest.Set(t, "db.port", 6543)
+func Set(t testing.TB, key string, value any) {
+	t.Helper()
+
+	restore, err := zfg.Override(key, zfg.ToString(value), Source)
+	if err != nil {
+		t.Fatalf("zfgtest: set %q: %v", key, err)
+	}
+
+	t.Cleanup(restore)
+}
+
+// AssertShow reports a test error if zfg.Show() differs from want.
+// Leading and trailing whitespace of both is ignored, so want may be a raw string literal.
+func AssertShow(t testing.TB, want string) {
+	t.Helper()
+
+	got := zfg.Show()
+	if strings.TrimSpace(got) != strings.TrimSpace(want) {
+		t.Errorf("zfgtest: unexpected Show output:\n--- want\n%s\n--- got\n%s", strings.TrimSpace(want), strings.TrimSpace(got))
+	}
+}
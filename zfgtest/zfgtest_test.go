@@ -0,0 +1,131 @@
+package zfgtest_test
+
+import (
+	"fmt"
+	"testing"
+
+	zfg "github.com/chaindead/zerocfg"
+	"github.com/chaindead/zerocfg/zfgtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	port     = zfg.Int("db.port", 5432, "database port", zfg.Alias("p"))
+	password = zfg.Str("db.password", "", "database password", zfg.Secret())
+	hosts    = zfg.Strs("hosts", []string{"a"}, "hosts")
+	limits   = zfg.Map("limits", map[string]any{"max": 10}, "limits")
+	addr     = zfg.IP("addr", "", "address")
+)
+
+// parse runs zfg.Parse without flags, as go test flags of the test binary are unknown keys.
+func parse(t *testing.T, ps ...zfg.Provider) {
+	t.Helper()
+
+	require.NoError(t, zfg.ParseWith(zfg.Providers(ps...), zfg.NoFlags()))
+}
+
+func TestMapAndReset(t *testing.T) {
+	t.Cleanup(zfgtest.Reset)
+
+	parse(t, zfgtest.Map{
+		"p":           6543,
+		"db.password": "qwerty",
+		"hosts":       []string{"b", "c"},
+		"limits":      map[string]any{"min": 1},
+		"addr":        "10.0.0.1",
+	})
+
+	assert.Equal(t, 6543, *port)
+	assert.Equal(t, "qwerty", *password)
+	assert.Equal(t, []string{"b", "c"}, *hosts)
+	assert.Equal(t, map[string]any{"min": float64(1)}, limits)
+
+	zfgtest.Reset()
+
+	assert.Equal(t, 5432, *port)
+	assert.Equal(t, "", *password)
+	assert.Equal(t, []string{"a"}, *hosts)
+	assert.Equal(t, map[string]any{"max": 10}, limits)
+	assert.Nil(t, *addr)
+
+	// Parse can be called again after Reset
+	parse(t, zfgtest.Map{"db.port": 1})
+	assert.Equal(t, 1, *port)
+}
+
+func TestSet(t *testing.T) {
+	t.Run("override", func(t *testing.T) {
+		zfgtest.Set(t, "p", 7000)
+		zfgtest.Set(t, "hosts", []string{"x"})
+		zfgtest.Set(t, "addr", "10.0.0.1")
+
+		assert.Equal(t, 7000, *port)
+		assert.Equal(t, []string{"x"}, *hosts)
+		assert.Equal(t, "10.0.0.1", addr.String())
+		e, _ := zfg.Snapshot().Get("db.port")
+		assert.Equal(t, zfgtest.Source, e.Source)
+	})
+
+	assert.Equal(t, 5432, *port)
+	assert.Equal(t, []string{"a"}, *hosts)
+	assert.Nil(t, *addr)
+	e, _ := zfg.Snapshot().Get("db.port")
+	assert.True(t, e.IsDefault)
+
+	rec := &recorder{TB: t}
+	func() {
+		defer func() { _ = recover() }()
+		zfgtest.Set(rec, "missing", 1)
+	}()
+	assert.Contains(t, rec.msg, `zfgtest: set "missing": no such key`)
+}
+
+func TestSet_Parallel(t *testing.T) {
+	t.Run("port", func(t *testing.T) {
+		t.Parallel()
+		zfgtest.Set(t, "db.port", 1)
+		assert.Equal(t, 1, *port)
+	})
+
+	t.Run("password", func(t *testing.T) {
+		t.Parallel()
+		zfgtest.Set(t, "db.password", "secret")
+		assert.Equal(t, "secret", *password)
+	})
+}
+
+func TestAssertShow(t *testing.T) {
+	zfgtest.Set(t, "db.port", 6543)
+
+	want := `
+addr: <nil> # address
+db:
+  password: <secret> # database password
+  port: 6543 # database port
+hosts: '["a"]' # hosts
+limits: '{"max":10}' # limits
+`
+	zfgtest.AssertShow(t, want)
+
+	rec := &recorder{TB: t}
+	zfgtest.AssertShow(rec, "db: {}")
+	assert.Contains(t, rec.msg, "unexpected Show output")
+}
+
+// recorder captures failures reported to testing.TB.
+type recorder struct {
+	testing.TB
+	msg string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.msg = fmt.Sprintf(format, args...)
+}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.msg = fmt.Sprintf(format, args...)
+	panic("fatal")
+}